@@ -0,0 +1,369 @@
+package assignment
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"strconv"
+	"strings"
+)
+
+// Problem - задача о назначениях: Matrix[i][j] - стоимость (или прибыль при
+// IsMaximization) назначения исполнителя i на работу j. Матрица дополняется
+// нулями до квадратной размерности N, Assignment[i] - работа исполнителя i
+type Problem struct {
+	Rows, Cols     int
+	N              int
+	Matrix         [][]*fractional.Fraction
+	IsMaximization bool
+	Reduced        [][]*fractional.Fraction
+	Assignment     []int
+	coveredRows    []bool
+	coveredCols    []bool
+}
+
+// Scan считывает задачу: первая строка - число исполнителей n, работ m и
+// необязательно max для матрицы прибылей, затем n строк по m значений
+func Scan(r io.Reader) (*Problem, error) {
+	scanner := bufio.NewScanner(r)
+	var lines [][]string
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || len(lines[0]) < 2 || len(lines[0]) > 3 {
+		return nil, fmt.Errorf("expected header: <rows> <cols> [min|max]")
+	}
+	rows, err := strconv.Atoi(lines[0][0])
+	if err != nil {
+		return nil, err
+	}
+	cols, err := strconv.Atoi(lines[0][1])
+	if err != nil {
+		return nil, err
+	}
+	if rows < 1 || cols < 1 {
+		return nil, fmt.Errorf("expected positive numbers of rows and columns, got %d and %d", rows, cols)
+	}
+	isMaximization := false
+	if len(lines[0]) == 3 {
+		switch lines[0][2] {
+		case "min":
+		case "max":
+			isMaximization = true
+		default:
+			return nil, fmt.Errorf("header: expected max or min, got %s", lines[0][2])
+		}
+	}
+	if len(lines) != rows+1 {
+		return nil, fmt.Errorf("expected %d matrix rows, got %d", rows, len(lines)-1)
+	}
+
+	matrix := make([][]*fractional.Fraction, rows)
+	for i := range rows {
+		if len(lines[i+1]) != cols {
+			return nil, fmt.Errorf("row %d: expected %d values, got %d", i+1, cols, len(lines[i+1]))
+		}
+		matrix[i] = make([]*fractional.Fraction, cols)
+		for j, field := range lines[i+1] {
+			if matrix[i][j], err = fractional.Parse(field); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return New(matrix, isMaximization)
+}
+
+// New создаёт задачу, дополняя прямоугольную матрицу нулевыми строками или столбцами
+func New(matrix [][]*fractional.Fraction, isMaximization bool) (*Problem, error) {
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		return nil, fmt.Errorf("matrix is empty")
+	}
+	p := &Problem{
+		Rows:           len(matrix),
+		Cols:           len(matrix[0]),
+		IsMaximization: isMaximization,
+	}
+	p.N = max(p.Rows, p.Cols)
+	p.Matrix = make([][]*fractional.Fraction, p.N)
+	for i := range p.N {
+		p.Matrix[i] = make([]*fractional.Fraction, p.N)
+		for j := range p.N {
+			p.Matrix[i][j] = fractional.ZeroValue
+		}
+		if i < p.Rows {
+			if len(matrix[i]) != p.Cols {
+				return nil, fmt.Errorf("row %d has %d values, expected %d", i+1, len(matrix[i]), p.Cols)
+			}
+			copy(p.Matrix[i], matrix[i])
+		}
+	}
+	return p, nil
+}
+
+// Solve решает задачу венгерским методом. При verbose печатается матрица
+// после каждой редукции и каждого покрытия нулей линиями
+func (p *Problem) Solve(verbose bool) {
+	p.Reduced = make([][]*fractional.Fraction, p.N)
+	// Для задачи на максимум прибыль переводится в потери: max - c[i][j]
+	var largest *fractional.Fraction
+	for i := range p.N {
+		for j := range p.N {
+			if largest == nil || p.Matrix[i][j].GreaterThan(*largest) {
+				largest = p.Matrix[i][j]
+			}
+		}
+	}
+	for i := range p.N {
+		p.Reduced[i] = make([]*fractional.Fraction, p.N)
+		for j := range p.N {
+			p.Reduced[i][j] = p.Matrix[i][j]
+			if p.IsMaximization {
+				p.Reduced[i][j] = largest.Subtract(*p.Matrix[i][j])
+			}
+		}
+	}
+	p.coveredRows, p.coveredCols = nil, nil
+	if verbose {
+		fmt.Printf("%s\n\n", p)
+	}
+
+	for i := range p.N {
+		smallest := p.Reduced[i][0]
+		for j := range p.N {
+			if p.Reduced[i][j].LessThan(*smallest) {
+				smallest = p.Reduced[i][j]
+			}
+		}
+		for j := range p.N {
+			p.Reduced[i][j] = p.Reduced[i][j].Subtract(*smallest)
+		}
+	}
+	if verbose {
+		fmt.Printf("Row reduction:\n%s\n\n", p)
+	}
+	for j := range p.N {
+		smallest := p.Reduced[0][j]
+		for i := range p.N {
+			if p.Reduced[i][j].LessThan(*smallest) {
+				smallest = p.Reduced[i][j]
+			}
+		}
+		for i := range p.N {
+			p.Reduced[i][j] = p.Reduced[i][j].Subtract(*smallest)
+		}
+	}
+	if verbose {
+		fmt.Printf("Column reduction:\n%s\n\n", p)
+	}
+
+	for {
+		matchRow, matchCol := p.matchZeros()
+		p.Assignment = matchRow
+		size := 0
+		for _, j := range matchRow {
+			if j != -1 {
+				size++
+			}
+		}
+		if size == p.N {
+			p.coveredRows, p.coveredCols = nil, nil
+			return
+		}
+
+		p.cover(matchRow, matchCol)
+		var theta *fractional.Fraction
+		for i := range p.N {
+			for j := range p.N {
+				if !p.coveredRows[i] && !p.coveredCols[j] && (theta == nil || p.Reduced[i][j].LessThan(*theta)) {
+					theta = p.Reduced[i][j]
+				}
+			}
+		}
+		if verbose {
+			fmt.Printf("Zeros covered by %d lines, θ = %s:\n%s\n\n", size, theta, p)
+		}
+		for i := range p.N {
+			for j := range p.N {
+				switch {
+				case !p.coveredRows[i] && !p.coveredCols[j]:
+					p.Reduced[i][j] = p.Reduced[i][j].Subtract(*theta)
+				case p.coveredRows[i] && p.coveredCols[j]:
+					p.Reduced[i][j] = p.Reduced[i][j].Add(*theta)
+				}
+			}
+		}
+	}
+}
+
+// matchZeros находит наибольшее паросочетание по нулевым клеткам
+// приведённой матрицы методом увеличивающих путей
+func (p *Problem) matchZeros() ([]int, []int) {
+	matchRow := make([]int, p.N)
+	matchCol := make([]int, p.N)
+	for k := range p.N {
+		matchRow[k], matchCol[k] = -1, -1
+	}
+	var augment func(i int, visited []bool) bool
+	augment = func(i int, visited []bool) bool {
+		for j := range p.N {
+			if visited[j] || p.Reduced[i][j].NotEqual(*fractional.ZeroValue) {
+				continue
+			}
+			visited[j] = true
+			if matchCol[j] == -1 || augment(matchCol[j], visited) {
+				matchRow[i], matchCol[j] = j, i
+				return true
+			}
+		}
+		return false
+	}
+	for i := range p.N {
+		augment(i, make([]bool, p.N))
+	}
+	return matchRow, matchCol
+}
+
+// cover строит наименьшее покрытие нулей линиями по теореме Кёнига:
+// помечаются строки без назначения и всё, что достижимо из них по
+// чередующимся путям; линиями служат непомеченные строки и помеченные столбцы
+func (p *Problem) cover(matchRow, matchCol []int) {
+	markedRows := make([]bool, p.N)
+	markedCols := make([]bool, p.N)
+	var queue []int
+	for i, j := range matchRow {
+		if j == -1 {
+			markedRows[i] = true
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for j := range p.N {
+			if markedCols[j] || p.Reduced[i][j].NotEqual(*fractional.ZeroValue) {
+				continue
+			}
+			markedCols[j] = true
+			if k := matchCol[j]; k != -1 && !markedRows[k] {
+				markedRows[k] = true
+				queue = append(queue, k)
+			}
+		}
+	}
+	p.coveredRows = make([]bool, p.N)
+	p.coveredCols = markedCols
+	for i := range p.N {
+		p.coveredRows[i] = !markedRows[i]
+	}
+}
+
+// Total - суммарная стоимость (прибыль) найденного назначения
+func (p *Problem) Total() *fractional.Fraction {
+	total := fractional.ZeroValue
+	for i, j := range p.Assignment {
+		if j != -1 {
+			total = total.Add(*p.Matrix[i][j])
+		}
+	}
+	return total
+}
+
+// Pairs возвращает назначения исходной задачи без фиктивных строк и столбцов
+func (p *Problem) Pairs() [][2]int {
+	var pairs [][2]int
+	for i, j := range p.Assignment {
+		if i < p.Rows && j != -1 && j < p.Cols {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	return pairs
+}
+
+// ToTable строит эквивалентную задачу линейного программирования над
+// дополненной матрицей: переменные x_i_j, по одному назначению в каждой строке и
+// столбце. Ограничение последнего столбца линейно зависимо от остальных и опускается
+func (p *Problem) ToTable() *simplex.Table {
+	n := p.N
+	matrix := make([][]*fractional.Fraction, 0, 2*n-1)
+	comparisons := make([]simplex.Comparison, 0, 2*n-1)
+	var rowNames []string
+	constraint := func(name string, in func(i, j int) bool) {
+		row := make([]*fractional.Fraction, n*n+1)
+		for i := range n {
+			for j := range n {
+				row[i*n+j] = fractional.ZeroValue
+				if in(i, j) {
+					row[i*n+j] = fractional.OneValue
+				}
+			}
+		}
+		row[n*n] = fractional.OneValue
+		matrix = append(matrix, row)
+		comparisons = append(comparisons, simplex.EqualTo)
+		rowNames = append(rowNames, name)
+	}
+	for k := range n {
+		constraint(fmt.Sprintf("row%d", k+1), func(i, _ int) bool { return i == k })
+	}
+	for k := range n - 1 {
+		constraint(fmt.Sprintf("col%d", k+1), func(_, j int) bool { return j == k })
+	}
+
+	z := make([]*fractional.Fraction, n*n)
+	varNames := make([]string, n*n)
+	for i := range n {
+		for j := range n {
+			z[i*n+j] = p.Matrix[i][j]
+			varNames[i*n+j] = fmt.Sprintf("x%d_%d", i+1, j+1)
+		}
+	}
+	t := simplex.NewTable(matrix, comparisons, z, !p.IsMaximization)
+	t.VarNames = varNames
+	t.RowNames = rowNames
+	return t
+}
+
+func (p *Problem) String() string {
+	var s string
+	offset := 8
+
+	s += fmt.Sprintf("%*s|", 5, "")
+	for j := range p.N {
+		mark := " "
+		if p.coveredCols != nil && p.coveredCols[j] {
+			mark = "+"
+		}
+		s += fmt.Sprintf("%*s%s", offset-1, fmt.Sprintf("B%d", j+1), mark)
+	}
+	for i := range p.N {
+		mark := " "
+		if p.coveredRows != nil && p.coveredRows[i] {
+			mark = "+"
+		}
+		s += fmt.Sprintf("\n %-3s%s|", fmt.Sprintf("A%d", i+1), mark)
+		for j := range p.N {
+			s += fmt.Sprintf("%*s ", offset-1, p.Reduced[i][j])
+		}
+	}
+	return s
+}
+
+// Report описывает найденное назначение
+func (p *Problem) Report() string {
+	var s string
+	for _, pair := range p.Pairs() {
+		s += fmt.Sprintf("A%d -> B%d (%s)\n", pair[0]+1, pair[1]+1, p.Matrix[pair[0]][pair[1]])
+	}
+	if p.IsMaximization {
+		return s + fmt.Sprintf("Fmax = %s", p.Total())
+	}
+	return s + fmt.Sprintf("Fmin = %s", p.Total())
+}
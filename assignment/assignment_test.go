@@ -0,0 +1,83 @@
+package assignment
+
+import (
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"strings"
+	"testing"
+)
+
+// TestSolve проверяет оптимальное назначение венгерским методом и его
+// совпадение с оптимумом той же задачи, решённой симплекс-методом
+func TestSolve(t *testing.T) {
+	for _, c := range []struct {
+		source, total string
+		pairs         int
+	}{
+		{"3 3\n4 1 3\n2 0 5\n3 2 2\n", "5", 3},
+		{"3 3 max\n4 1 3\n2 0 5\n3 2 2\n", "11", 3},
+		{"3 3 min\n9 2 7\n6 4 3\n5 8 1\n", "9", 3},
+		{"2 3\n1 2 3\n3 1 2\n", "2", 2},
+		{"3 2\n1 3\n2 1\n3 2\n", "2", 2},
+	} {
+		p, err := Scan(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		p.Solve(false)
+		if total := p.Total().String(); total != c.total {
+			t.Errorf("%q: F = %s, expected %s", c.source, total, c.total)
+		}
+		if pairs := p.Pairs(); len(pairs) != c.pairs {
+			t.Errorf("%q: pairs %v, expected %d", c.source, pairs, c.pairs)
+		}
+		jobs := make(map[int]bool)
+		for _, j := range p.Assignment {
+			jobs[j] = true
+		}
+		if len(jobs) != p.N {
+			t.Errorf("%q: assignment %v is not a permutation", c.source, p.Assignment)
+		}
+
+		table := p.ToTable()
+		table.SetOutput(io.Discard)
+		table.ToCanonicalForm()
+		basis, err := table.ToBasis()
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		m := simplex.New(basis)
+		m.MaxIterations = 1000
+		if err := m.DualMethod(); err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		lp := fractional.ZeroValue
+		for k, x := range m.Values()[:p.N*p.N] {
+			lp = lp.Add(*x.Multiply(*p.Matrix[k/p.N][k%p.N]))
+		}
+		if lp.NotEqual(*p.Total()) {
+			t.Errorf("%q: simplex method gives %s, Hungarian method %s", c.source, lp, p.Total())
+		}
+	}
+}
+
+// TestScanErrors проверяет, что неверные заголовки и размеры дают ошибку
+func TestScanErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"3\n",
+		"2 2 maximum\n1 2\n3 4\n",
+		"2 2 max extra\n1 2\n3 4\n",
+		"0 2\n",
+		"-1 2\n",
+		"2 0\n\n\n",
+		"2 2\n1 2\n",
+		"2 2\n1 2\n3\n",
+		"2 2\n1 2\n3 x\n",
+	} {
+		if _, err := Scan(strings.NewReader(source)); err == nil {
+			t.Errorf("%q: expected an error", source)
+		}
+	}
+}
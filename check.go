@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"kw-algos/simplex"
+	"os"
+	"strings"
+)
+
+// runCheck проверяет итерацию, посчитанную вручную: пересчитывает таблицу
+// по правилу прямоугольника и печатает ячейки, в которых ответ неверен
+func runCheck(args []string) int {
+	fs := newFlagSet("check")
+	format := fs.String("f", "matrix", "(input format of a problem given as the start) matrix | algebraic | lp | mps")
+	pivot := fs.String("pivot", "", "claimed pivot: row and column by numbers (1-based) or names, e.g. \"2 3\" or \"x5 x1\"")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: kw-algos check -pivot \"<row> <col>\" [flags] <start> <claimed>")
+		fmt.Fprintln(fs.Output(), "\nThe start is a tableau as printed by solve, a JSON tableau or a problem;")
+		fmt.Fprintln(fs.Output(), "for a problem the first dual simplex tableau is used. The claimed table is")
+		fmt.Fprintln(fs.Output(), "a printed or a JSON tableau.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 2 || *pivot == "" {
+		fs.Usage()
+		return exitUsage
+	}
+
+	start, err := readStart(fs.Arg(0), *format)
+	if err != nil {
+		return failf(exitUsage, "%s: %s", fs.Arg(0), err)
+	}
+	claimed, err := readTableau(fs.Arg(1))
+	if err != nil {
+		return failf(exitUsage, "%s: %s", fs.Arg(1), err)
+	}
+	row, col, err := parsePivot(start.Method.Table, *pivot)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+
+	fmt.Printf("%s\n\n", start)
+	warnings, err := start.Check(row, col)
+	if err != nil {
+		fmt.Println(err)
+		return exitFailure
+	}
+	for _, w := range warnings {
+		fmt.Printf("warning: %s\n", w)
+	}
+	mismatches, err := start.Method.Table.CheckIteration(row, col, claimed)
+	if err != nil {
+		fmt.Println(err)
+		return exitFailure
+	}
+	if len(mismatches) == 0 {
+		fmt.Println("the table is correct")
+		return exitOK
+	}
+	fmt.Printf("%d wrong entries:\n", len(mismatches))
+	for _, m := range mismatches {
+		fmt.Println(m)
+	}
+	return exitFailure
+}
+
+// readStart считывает исходную таблицу. Задача приводится к канонической
+// форме и базису, как перед двойственным симплекс-методом
+func readStart(path, format string) (*simplex.Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isTableau(data) {
+		t, err := parseTableau(data)
+		if err != nil {
+			return nil, err
+		}
+		return &simplex.Session{Method: simplex.New(t)}, nil
+	}
+	t, err := readTable(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, err
+	}
+	t.SetOutput(io.Discard)
+	t.ToCanonicalForm()
+	table, err := t.ToBasis()
+	if err != nil {
+		return nil, err
+	}
+	return simplex.NewSession(table), nil
+}
+
+func readTableau(path string) (*simplex.Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !isTableau(data) {
+		return nil, fmt.Errorf("expected a printed or a JSON tableau")
+	}
+	return parseTableau(data)
+}
+
+func isTableau(data []byte) bool {
+	text := strings.TrimSpace(string(data))
+	return strings.HasPrefix(text, "{") || strings.HasPrefix(text, "B.V")
+}
+
+func parseTableau(data []byte) (*simplex.Table, error) {
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		return simplex.ReadTableauJSON(bytes.NewReader(data))
+	}
+	return simplex.ScanTableau(bytes.NewReader(data))
+}
@@ -3,6 +3,9 @@ package fractional
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 )
 
 type Fraction struct {
@@ -17,6 +20,7 @@ type integer interface {
 var (
 	ErrDivideByZero    = errors.New("denominator cannot be zero")
 	ErrZeroDenominator = errors.New("denominator cannot be zero")
+	ErrInvalidSyntax   = errors.New("invalid fraction syntax")
 
 	ZeroValue = &Fraction{
 		numerator: 0, denominator: 1,
@@ -51,6 +55,59 @@ func New[T, K integer](numerator T, denominator K) (*Fraction, error) {
 	}, nil
 }
 
+// Parse разбирает дробь в виде "n", "n/d" или десятичной записи "1.25"
+func Parse(s string) (*Fraction, error) {
+	s = strings.TrimSpace(s)
+	if numerator, denominator, ok := strings.Cut(s, "/"); ok {
+		n, err := parseInt(strings.TrimSpace(numerator))
+		if err != nil {
+			return ZeroValue, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+		}
+		d, err := parseInt(strings.TrimSpace(denominator))
+		if err != nil {
+			return ZeroValue, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+		}
+		return New(n, d)
+	}
+	if whole, frac, ok := strings.Cut(s, "."); ok {
+		if len(frac) == 0 || len(frac) > 18 || strings.ContainsAny(frac, "+-") {
+			return ZeroValue, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+		}
+		negative := strings.HasPrefix(whole, "-")
+		digits := strings.TrimLeft(whole, "+-")
+		if len(whole)-len(digits) > 1 {
+			return ZeroValue, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+		}
+		n, err := parseInt(digits + frac)
+		if err != nil {
+			return ZeroValue, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+		}
+		if negative {
+			n = -n
+		}
+		d := int64(1)
+		for range len(frac) {
+			d *= 10
+		}
+		return New(n, d)
+	}
+	n, err := parseInt(s)
+	if err != nil {
+		return ZeroValue, fmt.Errorf("%w: %q", ErrInvalidSyntax, s)
+	}
+	return New(n, 1)
+}
+
+// parseInt разбирает целое число. math.MinInt64 не принимается: у него нет
+// противоположного, и смена знака при нормализации дроби переполняется
+func parseInt(s string) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err == nil && n == math.MinInt64 {
+		return 0, strconv.ErrRange
+	}
+	return n, err
+}
+
 func (f1 *Fraction) Add(f2 Fraction) *Fraction {
 	m := lcm(f1.denominator, f2.denominator)
 	sum := &Fraction{
@@ -0,0 +1,144 @@
+package fractional
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// small строит дробь из случайных чисел так, чтобы в проверках с тремя
+// операндами не было переполнения int64
+func small(n int16, d uint8) *Fraction {
+	f, _ := New(n, int(d)+1)
+	return f
+}
+
+func normalized(f *Fraction) bool {
+	if f.denominator <= 0 {
+		return false
+	}
+	if f.numerator == 0 {
+		return f.denominator == 1
+	}
+	return gcd(abs(f.numerator), f.denominator) == 1
+}
+
+func check(t *testing.T, name string, property any) {
+	t.Helper()
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Errorf("%s: %s", name, err)
+	}
+}
+
+func TestFieldAxioms(t *testing.T) {
+	check(t, "a + b = b + a", func(an int16, ad uint8, bn int16, bd uint8) bool {
+		a, b := small(an, ad), small(bn, bd)
+		return a.Add(*b).Equal(*b.Add(*a))
+	})
+	check(t, "a * b = b * a", func(an int16, ad uint8, bn int16, bd uint8) bool {
+		a, b := small(an, ad), small(bn, bd)
+		return a.Multiply(*b).Equal(*b.Multiply(*a))
+	})
+	check(t, "(a + b) + c = a + (b + c)", func(an int16, ad uint8, bn int16, bd uint8, cn int16, cd uint8) bool {
+		a, b, c := small(an, ad), small(bn, bd), small(cn, cd)
+		return a.Add(*b).Add(*c).Equal(*a.Add(*b.Add(*c)))
+	})
+	check(t, "(a * b) * c = a * (b * c)", func(an int16, ad uint8, bn int16, bd uint8, cn int16, cd uint8) bool {
+		a, b, c := small(an, ad), small(bn, bd), small(cn, cd)
+		return a.Multiply(*b).Multiply(*c).Equal(*a.Multiply(*b.Multiply(*c)))
+	})
+	check(t, "a * (b + c) = a * b + a * c", func(an int16, ad uint8, bn int16, bd uint8, cn int16, cd uint8) bool {
+		a, b, c := small(an, ad), small(bn, bd), small(cn, cd)
+		return a.Multiply(*b.Add(*c)).Equal(*a.Multiply(*b).Add(*a.Multiply(*c)))
+	})
+	check(t, "a + 0 = a, a * 1 = a", func(an int16, ad uint8) bool {
+		a := small(an, ad)
+		return a.Add(*ZeroValue).Equal(*a) && a.Multiply(*OneValue).Equal(*a)
+	})
+	check(t, "a + (-a) = 0, a - a = 0", func(an int16, ad uint8) bool {
+		a := small(an, ad)
+		return a.Add(*a.Reverse()).Equal(*ZeroValue) && a.Subtract(*a).Equal(*ZeroValue)
+	})
+	check(t, "a * (1 / a) = 1", func(an int16, ad uint8) bool {
+		a := small(an, ad)
+		inverse, err := OneValue.Divide(*a)
+		if a.Equal(*ZeroValue) {
+			return err != nil
+		}
+		return err == nil && a.Multiply(*inverse).Equal(*OneValue)
+	})
+	check(t, "(a / b) * b = a", func(an int16, ad uint8, bn int16, bd uint8) bool {
+		a, b := small(an, ad), small(bn, bd)
+		if b.Equal(*ZeroValue) {
+			return true
+		}
+		q, err := a.Divide(*b)
+		return err == nil && q.Multiply(*b).Equal(*a)
+	})
+}
+
+func TestNormalization(t *testing.T) {
+	check(t, "New", func(n int32, d int32) bool {
+		f, err := New(n, d)
+		if d == 0 {
+			return err != nil
+		}
+		return err == nil && normalized(f) && big.NewRat(int64(n), int64(d)).Cmp(rat(f)) == 0
+	})
+	check(t, "results of operations", func(an int16, ad uint8, bn int16, bd uint8) bool {
+		a, b := small(an, ad), small(bn, bd)
+		results := []*Fraction{a.Add(*b), a.Subtract(*b), a.Multiply(*b), a.Reverse(), a.Abs()}
+		if b.NotEqual(*ZeroValue) {
+			q, _ := a.Divide(*b)
+			results = append(results, q)
+		}
+		for _, f := range results {
+			if !normalized(f) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestOrder(t *testing.T) {
+	check(t, "exactly one of a < b, a = b, a > b", func(an int16, ad uint8, bn int16, bd uint8) bool {
+		a, b := small(an, ad), small(bn, bd)
+		count := 0
+		for _, holds := range []bool{a.LessThan(*b), a.Equal(*b), a.GreaterThan(*b)} {
+			if holds {
+				count++
+			}
+		}
+		return count == 1 && a.LessThan(*b) == (rat(a).Cmp(rat(b)) < 0)
+	})
+}
+
+func rat(f *Fraction) *big.Rat {
+	return big.NewRat(f.numerator, f.denominator)
+}
+
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"0", "-7", "3/4", "-6/8", "4/-6", " 1 / 2 ", "1.25", "-0.5", ".5", "+2", "1/0", "1.", "--1", "9223372036854775807", "-9223372036854775808/-2"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := Parse(s)
+		if err != nil {
+			return
+		}
+		if !normalized(v) {
+			t.Fatalf("Parse(%q) = %d/%d is not normalized", s, v.numerator, v.denominator)
+		}
+		again, err := Parse(v.String())
+		if err != nil || again.NotEqual(*v) {
+			t.Fatalf("Parse(%q) = %s, which parses back as %v (%v)", s, v, again, err)
+		}
+		// big.Rat понимает все формы, которые принимает Parse, кроме пробелов
+		// вокруг "/" и экспоненты, которую Parse не принимает
+		if expected, ok := new(big.Rat).SetString(strings.ReplaceAll(strings.TrimSpace(s), " ", "")); ok && expected.Cmp(rat(v)) != 0 {
+			t.Fatalf("Parse(%q) = %s, expected %s", s, v, expected.RatString())
+		}
+	})
+}
@@ -0,0 +1,344 @@
+package games
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"strconv"
+	"strings"
+)
+
+// Game - матричная игра двух лиц с нулевой суммой: Payoff[i][j] - выигрыш
+// игрока A (строки) при выборе им стратегии i и игроком B (столбцы) стратегии j.
+// P и Q - оптимальные смешанные стратегии игроков, Value - цена игры
+type Game struct {
+	Rows, Cols int
+	Payoff     [][]*fractional.Fraction
+	P, Q       []*fractional.Fraction
+	Value      *fractional.Fraction
+	// Оставшиеся после удаления доминируемых стратегий строки и столбцы
+	activeRows, activeCols []int
+}
+
+// Scan считывает игру: первая строка - число стратегий игроков m и n, затем m строк по n выигрышей
+func Scan(r io.Reader) (*Game, error) {
+	scanner := bufio.NewScanner(r)
+	var lines [][]string
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || len(lines[0]) != 2 {
+		return nil, fmt.Errorf("expected header: <rows> <cols>")
+	}
+	rows, err := strconv.Atoi(lines[0][0])
+	if err != nil {
+		return nil, err
+	}
+	cols, err := strconv.Atoi(lines[0][1])
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) != rows+1 {
+		return nil, fmt.Errorf("expected %d payoff rows, got %d", rows, len(lines)-1)
+	}
+
+	payoff := make([][]*fractional.Fraction, rows)
+	for i := range rows {
+		if len(lines[i+1]) != cols {
+			return nil, fmt.Errorf("row %d: expected %d values, got %d", i+1, cols, len(lines[i+1]))
+		}
+		payoff[i] = make([]*fractional.Fraction, cols)
+		for j, field := range lines[i+1] {
+			if payoff[i][j], err = fractional.Parse(field); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return New(payoff)
+}
+
+// New создаёт игру по платёжной матрице
+func New(payoff [][]*fractional.Fraction) (*Game, error) {
+	if len(payoff) == 0 || len(payoff[0]) == 0 {
+		return nil, fmt.Errorf("payoff matrix is empty")
+	}
+	g := &Game{
+		Rows:   len(payoff),
+		Cols:   len(payoff[0]),
+		Payoff: payoff,
+	}
+	for i, row := range payoff {
+		if len(row) != g.Cols {
+			return nil, fmt.Errorf("row %d has %d values, expected %d", i+1, len(row), g.Cols)
+		}
+	}
+	g.activeRows = make([]int, g.Rows)
+	for i := range g.activeRows {
+		g.activeRows[i] = i
+	}
+	g.activeCols = make([]int, g.Cols)
+	for j := range g.activeCols {
+		g.activeCols[j] = j
+	}
+	return g, nil
+}
+
+// SaddlePoint ищет седловую точку: элемент, минимальный в своей строке и
+// максимальный в своём столбце. Тогда нижняя и верхняя цены игры совпадают
+func (g *Game) SaddlePoint() (int, int, bool) {
+	for i := range g.Rows {
+		for j := range g.Cols {
+			isSaddle := true
+			for k := range g.Cols {
+				if g.Payoff[i][k].LessThan(*g.Payoff[i][j]) {
+					isSaddle = false
+					break
+				}
+			}
+			for k := 0; isSaddle && k < g.Rows; k++ {
+				if g.Payoff[k][j].GreaterThan(*g.Payoff[i][j]) {
+					isSaddle = false
+				}
+			}
+			if isSaddle {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Reduce удаляет доминируемые стратегии: строку, не лучшую для игрока A
+// другой строки ни в одном столбце, и столбец, не лучший для игрока B
+// другого столбца ни в одной строке. Возвращает описание удалённых стратегий
+func (g *Game) Reduce() []string {
+	var removed []string
+	for changed := true; changed; {
+		changed = false
+		for _, i := range g.activeRows {
+			if k, ok := g.dominatingRow(i); ok {
+				removed = append(removed, fmt.Sprintf("A%d is dominated by A%d", i+1, k+1))
+				g.activeRows = remove(g.activeRows, i)
+				changed = true
+				break
+			}
+		}
+		for _, j := range g.activeCols {
+			if k, ok := g.dominatingCol(j); ok {
+				removed = append(removed, fmt.Sprintf("B%d is dominated by B%d", j+1, k+1))
+				g.activeCols = remove(g.activeCols, j)
+				changed = true
+				break
+			}
+		}
+	}
+	return removed
+}
+
+func (g *Game) dominatingRow(i int) (int, bool) {
+	for _, k := range g.activeRows {
+		if k == i {
+			continue
+		}
+		dominates := true
+		for _, j := range g.activeCols {
+			if g.Payoff[k][j].LessThan(*g.Payoff[i][j]) {
+				dominates = false
+				break
+			}
+		}
+		if dominates {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+func (g *Game) dominatingCol(j int) (int, bool) {
+	for _, k := range g.activeCols {
+		if k == j {
+			continue
+		}
+		dominates := true
+		for _, i := range g.activeRows {
+			if g.Payoff[i][k].GreaterThan(*g.Payoff[i][j]) {
+				dominates = false
+				break
+			}
+		}
+		if dominates {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+func remove(indexes []int, index int) []int {
+	var result []int
+	for _, i := range indexes {
+		if i != index {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// shift - величина, добавляемая ко всем выигрышам, чтобы цена игры стала положительной
+func (g *Game) shift() *fractional.Fraction {
+	smallest := g.Payoff[g.activeRows[0]][g.activeCols[0]]
+	for _, i := range g.activeRows {
+		for _, j := range g.activeCols {
+			if g.Payoff[i][j].LessThan(*smallest) {
+				smallest = g.Payoff[i][j]
+			}
+		}
+	}
+	if smallest.GreaterThan(*fractional.ZeroValue) {
+		return fractional.ZeroValue
+	}
+	return fractional.OneValue.Subtract(*smallest)
+}
+
+// Tables строит пару двойственных задач для оставшихся стратегий после сдвига
+// выигрышей на положительную величину: для игрока A
+// min Σp_i при Σ_i a_ij p_i >= 1, для игрока B max Σq_j при Σ_j a_ij q_j <= 1
+func (g *Game) Tables() (*simplex.Table, *simplex.Table) {
+	shift := g.shift()
+	m, n := len(g.activeRows), len(g.activeCols)
+
+	rowMatrix := make([][]*fractional.Fraction, n)
+	rowComparisons := make([]simplex.Comparison, n)
+	for k, j := range g.activeCols {
+		rowMatrix[k] = make([]*fractional.Fraction, m+1)
+		for l, i := range g.activeRows {
+			rowMatrix[k][l] = g.Payoff[i][j].Add(*shift)
+		}
+		rowMatrix[k][m] = fractional.OneValue
+		rowComparisons[k] = simplex.GreaterThanOrEqualTo
+	}
+	colMatrix := make([][]*fractional.Fraction, m)
+	colComparisons := make([]simplex.Comparison, m)
+	for l, i := range g.activeRows {
+		colMatrix[l] = make([]*fractional.Fraction, n+1)
+		for k, j := range g.activeCols {
+			colMatrix[l][k] = g.Payoff[i][j].Add(*shift)
+		}
+		colMatrix[l][n] = fractional.OneValue
+		colComparisons[l] = simplex.LessThanOrEqualTo
+	}
+
+	ones := func(size int) []*fractional.Fraction {
+		z := make([]*fractional.Fraction, size)
+		for k := range z {
+			z[k] = fractional.OneValue
+		}
+		return z
+	}
+	rowTable := simplex.NewTable(rowMatrix, rowComparisons, ones(m), true)
+	colTable := simplex.NewTable(colMatrix, colComparisons, ones(n), false)
+	for _, i := range g.activeRows {
+		rowTable.VarNames = append(rowTable.VarNames, fmt.Sprintf("p%d", i+1))
+	}
+	for _, j := range g.activeCols {
+		colTable.VarNames = append(colTable.VarNames, fmt.Sprintf("q%d", j+1))
+	}
+	return rowTable, colTable
+}
+
+// Solve находит оптимальные стратегии игроков и цену игры. При наличии
+// седловой точки стратегии чистые, иначе после удаления доминируемых
+// стратегий решаются задачи линейного программирования; их таблицы печатаются в w
+func (g *Game) Solve(w io.Writer) error {
+	g.P = make([]*fractional.Fraction, g.Rows)
+	for i := range g.P {
+		g.P[i] = fractional.ZeroValue
+	}
+	g.Q = make([]*fractional.Fraction, g.Cols)
+	for j := range g.Q {
+		g.Q[j] = fractional.ZeroValue
+	}
+
+	if i, j, ok := g.SaddlePoint(); ok {
+		fmt.Fprintf(w, "saddle point (A%d, B%d)\n", i+1, j+1)
+		g.P[i], g.Q[j] = fractional.OneValue, fractional.OneValue
+		g.Value = g.Payoff[i][j]
+		return nil
+	}
+
+	for _, s := range g.Reduce() {
+		fmt.Fprintln(w, s)
+	}
+	rowTable, colTable := g.Tables()
+
+	fmt.Fprintln(w, "Player A:")
+	p, err := solve(rowTable, w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nPlayer B:")
+	q, err := solve(colTable, w)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+
+	// Цена сдвинутой игры обратна Σp_i = Σq_j, стратегии - p_i и q_j, умноженные на неё
+	sum := fractional.ZeroValue
+	for _, x := range p {
+		sum = sum.Add(*x)
+	}
+	value, err := fractional.OneValue.Divide(*sum)
+	if err != nil {
+		return err
+	}
+	for l, i := range g.activeRows {
+		g.P[i] = p[l].Multiply(*value)
+	}
+	for k, j := range g.activeCols {
+		g.Q[j] = q[k].Multiply(*value)
+	}
+	g.Value = value.Subtract(*g.shift())
+	return nil
+}
+
+func solve(t *simplex.Table, w io.Writer) ([]*fractional.Fraction, error) {
+	t.SetOutput(w)
+	fmt.Fprintf(w, "%s\n", t.ToCanonicalForm())
+	basis, err := t.ToBasis()
+	if err != nil {
+		return nil, err
+	}
+	method := simplex.New(basis)
+	if err := method.DualMethod(); err != nil {
+		return nil, err
+	}
+	return method.Values()[:t.Vars], nil
+}
+
+func (g *Game) String() string {
+	var s string
+	offset := 8
+
+	s += fmt.Sprintf("%*s|", 5, "")
+	for j := range g.Cols {
+		s += fmt.Sprintf("%*s", offset, fmt.Sprintf("B%d", j+1))
+	}
+	for i := range g.Rows {
+		s += fmt.Sprintf("\n %-4s|", fmt.Sprintf("A%d", i+1))
+		for j := range g.Cols {
+			s += fmt.Sprintf("%*s", offset, g.Payoff[i][j])
+		}
+	}
+	if g.Value != nil {
+		s += fmt.Sprintf("\nP = %v\nQ = %v\nv = %s", g.P, g.Q, g.Value)
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+package games
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestSolve проверяет цену игры и оптимальные стратегии игроков: чистые при
+// седловой точке и смешанные после удаления доминируемых стратегий
+func TestSolve(t *testing.T) {
+	for _, c := range []struct {
+		source, value, p, q string
+	}{
+		{"3 3\n0 -1 1\n1 0 -1\n-1 1 0\n", "0", "1/3 1/3 1/3", "1/3 1/3 1/3"},
+		{"2 3\n4 5 6\n3 7 2\n", "4", "1 0", "1 0 0"},
+		{"2 2\n2 -1\n-1 1\n", "1/5", "2/5 3/5", "2/5 3/5"},
+		{"3 2\n3 -1\n-1 1\n0 -2\n", "1/3", "1/3 2/3 0", "1/3 2/3"},
+	} {
+		g, err := Scan(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		if err := g.Solve(io.Discard); err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		var p, q []string
+		for _, v := range g.P {
+			p = append(p, v.String())
+		}
+		for _, v := range g.Q {
+			q = append(q, v.String())
+		}
+		if g.Value.String() != c.value || strings.Join(p, " ") != c.p || strings.Join(q, " ") != c.q {
+			t.Errorf("%q: v = %s, P = %v, Q = %v, expected %s, %s and %s", c.source, g.Value, p, q, c.value, c.p, c.q)
+		}
+	}
+}
+
+// TestScanErrors проверяет, что неверные заголовки и размеры дают ошибку
+func TestScanErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"2\n",
+		"0 2\n",
+		"-1 2\n1 2\n",
+		"1 -1\n1\n",
+		"2 2\n1 2\n",
+		"2 2\n1 2\n3\n",
+		"1 2\n1 x\n",
+	} {
+		if _, err := Scan(strings.NewReader(source)); err == nil {
+			t.Errorf("%q: expected an error", source)
+		}
+	}
+}
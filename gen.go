@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"kw-algos/gen"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runGen печатает случайные задачи в формате Scan с аннотациями для verify
+func runGen(args []string) int {
+	fs := newFlagSet("gen")
+	o := gen.DefaultOptions
+	count := fs.Int("n", 1, "number of problems")
+	fs.IntVar(&o.Rows, "rows", o.Rows, "number of constraints")
+	fs.IntVar(&o.Vars, "vars", o.Vars, "number of variables")
+	fs.Int64Var(&o.Min, "min", o.Min, "smallest coefficient")
+	fs.Int64Var(&o.Max, "max", o.Max, "largest coefficient")
+	mix := fs.String("mix", "1:1:0", "weights of <=, >= and = constraints")
+	outcome := fs.String("outcome", "unique", "unique | alternative | unbounded | infeasible | degenerate")
+	fs.BoolVar(&o.Minimize, "minimize", false, "generate minimization problems")
+	seed := fs.Int64("seed", 0, "random seed, 0 - current time")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		return failf(exitUsage, "gen takes no file arguments")
+	}
+
+	var err error
+	if o.Outcome, err = gen.ParseOutcome(*outcome); err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	weights := strings.Split(*mix, ":")
+	if len(weights) != 3 {
+		return failf(exitUsage, "mix: expected three weights like 2:1:0")
+	}
+	for k, target := range []*int{&o.LessEqual, &o.GreaterEqual, &o.Equal} {
+		if *target, err = strconv.Atoi(weights[k]); err != nil {
+			return failf(exitUsage, "mix: %s", err)
+		}
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+
+	fmt.Printf("// seed %d, %s\n\n", *seed, o.Outcome)
+	for k := range *count {
+		t, value, err := gen.Generate(o, rng)
+		if err != nil {
+			return failf(exitFailure, "problem %d: %s", k+1, err)
+		}
+		if _, err := t.WriteTo(os.Stdout); err != nil {
+			return failf(exitFailure, "%s", err)
+		}
+		switch o.Outcome {
+		case gen.Unique, gen.Degenerate:
+			fmt.Printf("//%s\n\n", value)
+		case gen.Alternative:
+			fmt.Print("//inf\n\n")
+		default:
+			fmt.Print("//no\n\n")
+		}
+	}
+	return exitOK
+}
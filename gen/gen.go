@@ -0,0 +1,238 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"math/rand"
+)
+
+var ErrNotGenerated = errors.New("no problem with the requested outcome was generated")
+
+// maxIterations ограничивает симплекс-метод при проверке сгенерированной задачи
+const maxIterations = 1000
+
+type Outcome int
+
+const (
+	// Unique - единственное невырожденное оптимальное решение
+	Unique Outcome = iota
+	// Alternative - оптимальное решение не единственное
+	Alternative
+	// Unbounded - целевая функция неограничена на допустимом множестве
+	Unbounded
+	// Infeasible - допустимое множество пусто
+	Infeasible
+	// Degenerate - оптимальное решение единственное, но в оптимальном базисе
+	// есть нулевая базисная переменная
+	Degenerate
+)
+
+var outcomeNames = [...]string{"unique", "alternative", "unbounded", "infeasible", "degenerate"}
+
+func (o Outcome) String() string {
+	return outcomeNames[o]
+}
+
+// ParseOutcome возвращает исход по названию
+func ParseOutcome(name string) (Outcome, error) {
+	for k, n := range outcomeNames {
+		if n == name {
+			return Outcome(k), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown outcome: %s", name)
+}
+
+// Options - параметры генерации: размер задачи, диапазон целых коэффициентов
+// [Min, Max], веса ограничений <=, >= и = и требуемый исход
+type Options struct {
+	Rows, Vars                     int
+	Min, Max                       int64
+	LessEqual, GreaterEqual, Equal int
+	Minimize                       bool
+	Outcome                        Outcome
+	// Attempts - число попыток, после которого генерация прекращается
+	Attempts int
+}
+
+// DefaultOptions - задача 3x3 с коэффициентами от -5 до 10 и неравенствами обоих знаков
+var DefaultOptions = Options{
+	Rows: 3, Vars: 3,
+	Min: -5, Max: 10,
+	LessEqual: 1, GreaterEqual: 1,
+	Attempts: 10000,
+}
+
+// problem - коэффициенты задачи, из которых при каждой проверке собирается новая таблица
+type problem struct {
+	matrix      [][]*fractional.Fraction
+	comparisons []simplex.Comparison
+	z           []*fractional.Fraction
+	minimize    bool
+}
+
+func (p *problem) table() *simplex.Table {
+	return simplex.NewTable(p.matrix, p.comparisons, p.z, p.minimize)
+}
+
+// Generate строит случайные задачи, пока исход одной из них, найденный
+// решением, не совпадёт с требуемым. Для оптимальных исходов возвращается
+// и значение целевой функции
+func Generate(o Options, rng *rand.Rand) (*simplex.Table, *fractional.Fraction, error) {
+	if o.Rows < 1 || o.Vars < 1 || o.Min > o.Max {
+		return nil, nil, fmt.Errorf("invalid size or coefficient range")
+	}
+	if o.LessEqual < 0 || o.GreaterEqual < 0 || o.Equal < 0 || o.LessEqual+o.GreaterEqual+o.Equal == 0 {
+		return nil, nil, fmt.Errorf("invalid constraint mix")
+	}
+	for range max(o.Attempts, 1) {
+		p := o.random(rng)
+		outcome, value, err := Classify(p.table())
+		if err != nil {
+			continue
+		}
+		if outcome == o.Outcome {
+			return p.table(), value, nil
+		}
+	}
+	return nil, nil, ErrNotGenerated
+}
+
+// random строит задачу вокруг случайной неотрицательной целой точки x0, чтобы
+// задача была совместна, и подстраивает её под требуемый исход
+func (o Options) random(rng *rand.Rand) *problem {
+	p := &problem{minimize: o.Minimize}
+	x0 := make([]int64, o.Vars)
+	for j := range x0 {
+		// Для вырожденного исхода точка чаще лежит на осях
+		if o.Outcome != Degenerate || rng.Intn(2) == 0 {
+			x0[j] = rng.Int63n(max(o.Max, 1) + 1)
+		}
+	}
+	for range o.Rows {
+		row := make([]*fractional.Fraction, o.Vars+1)
+		var value int64
+		for j := range o.Vars {
+			a := o.coefficient(rng)
+			row[j], _ = fractional.New(a, 1)
+			value += a * x0[j]
+		}
+		comparison := o.comparison(rng)
+		slack := rng.Int63n(max(o.Max, 1) + 1)
+		if o.Outcome == Degenerate {
+			slack = 0
+		}
+		switch comparison {
+		case simplex.LessThanOrEqualTo:
+			value += slack
+		case simplex.GreaterThanOrEqualTo:
+			value -= slack
+		}
+		row[o.Vars], _ = fractional.New(value, 1)
+		p.matrix = append(p.matrix, row)
+		p.comparisons = append(p.comparisons, comparison)
+	}
+
+	switch o.Outcome {
+	case Alternative:
+		// Целевая функция параллельна ограничению, которое может стать оптимальной гранью
+		k := rng.Intn(o.Rows)
+		scale, _ := fractional.New(rng.Int63n(3)+1, 1)
+		if p.comparisons[k] == simplex.GreaterThanOrEqualTo != o.Minimize {
+			scale = scale.Reverse()
+		}
+		for j := range o.Vars {
+			p.z = append(p.z, p.matrix[k][j].Multiply(*scale))
+		}
+	case Infeasible:
+		// Последнее ограничение противоречит первому
+		k := o.Rows - 1
+		shift, _ := fractional.New(rng.Int63n(max(o.Max, 1))+1, 1)
+		row := append([]*fractional.Fraction(nil), p.matrix[0]...)
+		if p.comparisons[0] == simplex.GreaterThanOrEqualTo {
+			row[o.Vars] = row[o.Vars].Subtract(*shift)
+			p.comparisons[k] = simplex.LessThanOrEqualTo
+		} else {
+			row[o.Vars] = row[o.Vars].Add(*shift)
+			p.comparisons[k] = simplex.GreaterThanOrEqualTo
+		}
+		p.matrix[k] = row
+		fallthrough
+	default:
+		for range o.Vars {
+			c, _ := fractional.New(o.coefficient(rng), 1)
+			p.z = append(p.z, c)
+		}
+	}
+	return p
+}
+
+func (o Options) coefficient(rng *rand.Rand) int64 {
+	return o.Min + rng.Int63n(o.Max-o.Min+1)
+}
+
+func (o Options) comparison(rng *rand.Rand) simplex.Comparison {
+	k := rng.Intn(o.LessEqual + o.GreaterEqual + o.Equal)
+	switch {
+	case k < o.LessEqual:
+		return simplex.LessThanOrEqualTo
+	case k < o.LessEqual+o.GreaterEqual:
+		return simplex.GreaterThanOrEqualTo
+	default:
+		return simplex.EqualTo
+	}
+}
+
+// Classify решает задачу двойственным симплекс-методом по правилу Бленда и
+// определяет её исход по проверенному доказательству ответа. Для оптимальных
+// исходов возвращается значение целевой функции
+func Classify(t *simplex.Table) (Outcome, *fractional.Fraction, error) {
+	t.SetOutput(io.Discard)
+	t.ToCanonicalForm()
+	objective := t.Objective()
+	value := t.ObjectiveConstant()
+	basis, err := t.ToBasis()
+	if errors.Is(err, simplex.ErrNoSolutions) {
+		// Метод Жордана-Гаусса нашёл противоречивую систему уравнений
+		return Infeasible, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	method := simplex.New(basis)
+	method.Rule = simplex.Bland
+	method.MaxIterations = maxIterations
+	if err := method.DualMethod(); err != nil && !errors.Is(err, simplex.ErrNoSolutions) {
+		return 0, nil, err
+	}
+	certificate, err := method.Verify()
+	if err != nil {
+		return 0, nil, err
+	}
+	switch {
+	case certificate.Farkas != nil:
+		return Infeasible, nil, nil
+	case certificate.Ray != nil:
+		return Unbounded, nil, nil
+	}
+
+	for j, x := range method.Values() {
+		value = value.Add(*objective[j].Multiply(*x))
+	}
+	if method.Face.Truncated {
+		// Без полного перебора оптимальных базисов нельзя отличить единственный оптимум от альтернативного
+		return 0, nil, simplex.ErrFaceTruncated
+	}
+	if len(method.Face.Vertices) > 1 || len(method.Face.Rays) > 0 {
+		return Alternative, value, nil
+	}
+	for i := range method.Table.Rows {
+		if method.Table.Matrix[i][method.Table.Cols-1].Equal(*fractional.ZeroValue) {
+			return Degenerate, value, nil
+		}
+	}
+	return Unique, value, nil
+}
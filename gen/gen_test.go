@@ -0,0 +1,98 @@
+package gen
+
+import (
+	"errors"
+	"kw-algos/simplex"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestClassify проверяет исход и значение целевой функции задач с известным ответом
+func TestClassify(t *testing.T) {
+	for _, c := range []struct {
+		source  string
+		outcome Outcome
+		value   string
+	}{
+		{"max: x1 + 3x2;\nx1 + x2 <= 4;\nx1 + 2x2 <= 6;\n", Unique, "9"},
+		{"max: x1 + x2;\nx1 + x2 <= 4;\n", Alternative, "4"},
+		{"max: x1 + x2;\nx1 - x2 <= 1;\n", Unbounded, ""},
+		{"max: x1;\nx1 + x2 <= 1;\nx1 + x2 >= 2;\n", Infeasible, ""},
+		{"max: x1 + x2;\nx1 + 2x2 <= 4;\n2x1 + x2 <= 4;\nx1 <= 4/3;\n", Degenerate, "8/3"},
+	} {
+		table, err := simplex.ScanAlgebraic(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		outcome, value, err := Classify(table)
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		s := ""
+		if value != nil && (outcome == Unique || outcome == Alternative || outcome == Degenerate) {
+			s = value.String()
+		}
+		if outcome != c.outcome || s != c.value {
+			t.Errorf("%q: %s with value %q, expected %s with %q", c.source, outcome, s, c.outcome, c.value)
+		}
+	}
+}
+
+// TestGenerate проверяет, что сгенерированная задача при повторном решении
+// даёт требуемый исход и то же значение целевой функции
+func TestGenerate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, outcome := range []Outcome{Unique, Alternative, Unbounded, Infeasible, Degenerate} {
+		for _, minimize := range []bool{false, true} {
+			o := DefaultOptions
+			o.Outcome, o.Minimize = outcome, minimize
+			table, value, err := Generate(o, rng)
+			if err != nil {
+				t.Fatalf("%s, minimize %t: %s", outcome, minimize, err)
+			}
+			if table.Rows != o.Rows || table.Vars != o.Vars || table.IsMinimizationProblem != minimize {
+				t.Errorf("%s: generated %dx%d, minimize %t", outcome, table.Rows, table.Vars, table.IsMinimizationProblem)
+			}
+			again, againValue, err := Classify(table)
+			if err != nil {
+				t.Fatalf("%s: %s", outcome, err)
+			}
+			if again != outcome || (value == nil) != (againValue == nil) || value != nil && value.NotEqual(*againValue) {
+				t.Errorf("%s: classified again as %s with value %v, generated with %v", outcome, again, againValue, value)
+			}
+		}
+	}
+}
+
+// TestGenerateErrors проверяет отказ при неверных параметрах и исчерпании попыток
+func TestGenerateErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, change := range []func(*Options){
+		func(o *Options) { o.Rows = 0 },
+		func(o *Options) { o.Vars = 0 },
+		func(o *Options) { o.Min, o.Max = 5, 1 },
+		func(o *Options) { o.LessEqual, o.GreaterEqual, o.Equal = 0, 0, 0 },
+		func(o *Options) { o.Equal = -1 },
+	} {
+		o := DefaultOptions
+		change(&o)
+		if _, _, err := Generate(o, rng); err == nil || errors.Is(err, ErrNotGenerated) {
+			t.Errorf("%+v: expected an options error, got %v", o, err)
+		}
+	}
+
+	o := DefaultOptions
+	o.Min, o.Max, o.Outcome, o.Attempts = 0, 0, Unbounded, 10
+	if _, _, err := Generate(o, rng); !errors.Is(err, ErrNotGenerated) {
+		t.Errorf("zero coefficients: expected ErrNotGenerated, got %v", err)
+	}
+	if _, err := ParseOutcome("bounded"); err == nil {
+		t.Error("expected an error for an unknown outcome")
+	}
+	for k, name := range outcomeNames {
+		if outcome, err := ParseOutcome(name); err != nil || outcome != Outcome(k) {
+			t.Errorf("%s: got %v, %v", name, outcome, err)
+		}
+	}
+}
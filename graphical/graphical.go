@@ -0,0 +1,326 @@
+package graphical
+
+import (
+	"fmt"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"math"
+	"sort"
+)
+
+type Status int
+
+const (
+	Optimal Status = iota
+	Unbounded
+	Infeasible
+)
+
+func (s Status) String() string {
+	return [...]string{"optimal", "unbounded", "infeasible"}[s]
+}
+
+// Point - точка или направление на плоскости x1, x2
+type Point struct {
+	X, Y *fractional.Fraction
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%s; %s)", p.X, p.Y)
+}
+
+func (p Point) equal(q Point) bool {
+	return p.X.Equal(*q.X) && p.Y.Equal(*q.Y)
+}
+
+// Constraint - ограничение A·x1 + B·x2 (знак) RHS
+type Constraint struct {
+	A, B, RHS  *fractional.Fraction
+	Comparison simplex.Comparison
+}
+
+func (c Constraint) value(p Point) *fractional.Fraction {
+	return c.A.Multiply(*p.X).Add(*c.B.Multiply(*p.Y))
+}
+
+func (c Constraint) satisfies(p Point) bool {
+	value := c.value(p)
+	switch c.Comparison {
+	case simplex.LessThanOrEqualTo:
+		return !value.GreaterThan(*c.RHS)
+	case simplex.GreaterThanOrEqualTo:
+		return !value.LessThan(*c.RHS)
+	default:
+		return value.Equal(*c.RHS)
+	}
+}
+
+// allows сообщает, можно ли неограниченно двигаться вдоль направления d, не нарушая ограничение
+func (c Constraint) allows(d Point) bool {
+	value := c.value(d)
+	switch c.Comparison {
+	case simplex.LessThanOrEqualTo:
+		return !value.GreaterThan(*fractional.ZeroValue)
+	case simplex.GreaterThanOrEqualTo:
+		return !value.LessThan(*fractional.ZeroValue)
+	default:
+		return value.Equal(*fractional.ZeroValue)
+	}
+}
+
+func (c Constraint) String() string {
+	return fmt.Sprintf("%s·x1 + %s·x2 %s %s", c.A, c.B, &c.Comparison, c.RHS)
+}
+
+// Problem - задача с двумя переменными: ограничения Constraints, неотрицательность
+// переменных, максимизация Gradient·x + Constant (для задачи на минимум - минимизация)
+type Problem struct {
+	Constraints    []Constraint
+	Gradient       Point
+	Constant       *fractional.Fraction
+	IsMinimization bool
+}
+
+// Solution - результат графического метода: вершины допустимого многоугольника
+// в порядке обхода, направления неограниченности допустимого множества Rays,
+// оптимальные вершины (одна - единственная точка, две - оптимальное ребро),
+// направление Direction, вдоль которого целевая функция неограничена либо
+// остаётся оптимальной на луче
+type Solution struct {
+	Status    Status
+	Vertices  []Point
+	Rays      []Point
+	Optimal   []Point
+	Direction *Point
+	Value     *fractional.Fraction
+}
+
+// New строит задачу по таблице с двумя переменными до приведения к канонической форме
+func New(t *simplex.Table) (*Problem, error) {
+	if t.Vars != 2 || t.Cols != 3 {
+		return nil, fmt.Errorf("graphical method needs a problem with 2 variables, got %d", t.Vars)
+	}
+	p := &Problem{
+		Gradient:       Point{t.Z[0], t.Z[1]},
+		Constant:       t.ObjectiveConstant(),
+		IsMinimization: t.IsMinimizationProblem,
+	}
+	for i, comparison := range t.Comparisons() {
+		p.Constraints = append(p.Constraints, Constraint{
+			A:          t.Matrix[i][0],
+			B:          t.Matrix[i][1],
+			RHS:        t.Matrix[i][2],
+			Comparison: comparison,
+		})
+	}
+	return p, nil
+}
+
+// bounds - все ограничения вместе с условиями неотрицательности x1 >= 0, x2 >= 0
+func (p *Problem) bounds() []Constraint {
+	constraints := append([]Constraint(nil), p.Constraints...)
+	for _, axis := range []Point{{fractional.OneValue, fractional.ZeroValue}, {fractional.ZeroValue, fractional.OneValue}} {
+		constraints = append(constraints, Constraint{
+			A:          axis.X,
+			B:          axis.Y,
+			RHS:        fractional.ZeroValue,
+			Comparison: simplex.GreaterThanOrEqualTo,
+		})
+	}
+	return constraints
+}
+
+// Solve находит вершины допустимого множества как допустимые точки пересечения
+// граничных прямых и выбирает среди них оптимальные
+func (p *Problem) Solve() *Solution {
+	constraints := p.bounds()
+	s := &Solution{
+		Vertices: vertices(constraints),
+		Rays:     rays(constraints),
+	}
+	if len(s.Vertices) == 0 {
+		s.Status = Infeasible
+		return s
+	}
+
+	gradient := p.Gradient
+	if p.IsMinimization {
+		gradient = Point{p.Gradient.X.Reverse(), p.Gradient.Y.Reverse()}
+	}
+	objective := func(q Point) *fractional.Fraction {
+		return gradient.X.Multiply(*q.X).Add(*gradient.Y.Multiply(*q.Y))
+	}
+	for _, ray := range s.Rays {
+		if objective(ray).GreaterThan(*fractional.ZeroValue) {
+			s.Status = Unbounded
+			s.Direction = &ray
+			return s
+		}
+	}
+
+	var best *fractional.Fraction
+	for _, v := range s.Vertices {
+		value := objective(v)
+		switch {
+		case best == nil || value.GreaterThan(*best):
+			best = value
+			s.Optimal = []Point{v}
+		case value.Equal(*best):
+			s.Optimal = append(s.Optimal, v)
+		}
+	}
+	for _, ray := range s.Rays {
+		if objective(ray).Equal(*fractional.ZeroValue) {
+			s.Direction = &ray
+			break
+		}
+	}
+	s.Status = Optimal
+	s.Value = p.Gradient.X.Multiply(*s.Optimal[0].X).Add(*p.Gradient.Y.Multiply(*s.Optimal[0].Y)).Add(*p.Constant)
+	return s
+}
+
+// vertices перебирает пары граничных прямых, оставляет допустимые точки
+// пересечения и упорядочивает их обходом против часовой стрелки
+func vertices(constraints []Constraint) []Point {
+	var points []Point
+	for i := range constraints {
+		for j := i + 1; j < len(constraints); j++ {
+			point, ok := intersect(constraints[i], constraints[j])
+			if !ok {
+				continue
+			}
+			feasible := true
+			for _, c := range constraints {
+				if !c.satisfies(point) {
+					feasible = false
+					break
+				}
+			}
+			if feasible && !containsPoint(points, point) {
+				points = append(points, point)
+			}
+		}
+	}
+	return orderByAngle(points)
+}
+
+// intersect решает систему из двух граничных прямых по правилу Крамера
+func intersect(c1, c2 Constraint) (Point, bool) {
+	determinant := c1.A.Multiply(*c2.B).Subtract(*c1.B.Multiply(*c2.A))
+	if determinant.Equal(*fractional.ZeroValue) {
+		return Point{}, false
+	}
+	x, _ := c1.RHS.Multiply(*c2.B).Subtract(*c1.B.Multiply(*c2.RHS)).Divide(*determinant)
+	y, _ := c1.A.Multiply(*c2.RHS).Subtract(*c1.RHS.Multiply(*c2.A)).Divide(*determinant)
+	return Point{x, y}, true
+}
+
+// rays находит крайние направления, вдоль которых допустимое множество
+// неограничено. Кандидаты - оси координат и направляющие граничных прямых
+func rays(constraints []Constraint) []Point {
+	candidates := []Point{
+		{fractional.OneValue, fractional.ZeroValue},
+		{fractional.ZeroValue, fractional.OneValue},
+	}
+	for _, c := range constraints {
+		candidates = append(candidates, Point{c.B, c.A.Reverse()}, Point{c.B.Reverse(), c.A})
+	}
+	var allowed []Point
+	for _, d := range candidates {
+		if d.X.Equal(*fractional.ZeroValue) && d.Y.Equal(*fractional.ZeroValue) {
+			continue
+		}
+		ok := true
+		for _, c := range constraints {
+			if !c.allows(d) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			allowed = append(allowed, normalize(d))
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	// Конус направлений лежит в первой четверти, крайние лучи - с наименьшим и наибольшим углом
+	sort.Slice(allowed, func(i, j int) bool { return angle(allowed[i]) < angle(allowed[j]) })
+	first, last := allowed[0], allowed[len(allowed)-1]
+	if first.equal(last) {
+		return []Point{first}
+	}
+	return []Point{first, last}
+}
+
+// normalize делит направление на наибольшую по модулю координату
+func normalize(d Point) Point {
+	scale := d.X.Abs()
+	if d.Y.Abs().GreaterThan(*scale) {
+		scale = d.Y.Abs()
+	}
+	x, _ := d.X.Divide(*scale)
+	y, _ := d.Y.Divide(*scale)
+	return Point{x, y}
+}
+
+func angle(d Point) float64 {
+	return math.Atan2(d.Y.Float64(), d.X.Float64())
+}
+
+func containsPoint(points []Point, point Point) bool {
+	for _, p := range points {
+		if p.equal(point) {
+			return true
+		}
+	}
+	return false
+}
+
+// orderByAngle упорядочивает вершины выпуклого многоугольника по углу относительно его центра
+func orderByAngle(points []Point) []Point {
+	var cx, cy float64
+	for _, p := range points {
+		cx += p.X.Float64()
+		cy += p.Y.Float64()
+	}
+	cx /= float64(len(points))
+	cy /= float64(len(points))
+	sort.SliceStable(points, func(i, j int) bool {
+		return math.Atan2(points[i].Y.Float64()-cy, points[i].X.Float64()-cx) <
+			math.Atan2(points[j].Y.Float64()-cy, points[j].X.Float64()-cx)
+	})
+	return points
+}
+
+func (s *Solution) String() string {
+	if s.Status == Infeasible {
+		return "no feasible points"
+	}
+	str := "vertices:"
+	for _, v := range s.Vertices {
+		str += " " + v.String()
+	}
+	if len(s.Rays) > 0 {
+		str += "\nunbounded along:"
+		for _, r := range s.Rays {
+			str += " " + r.String()
+		}
+	}
+	switch s.Status {
+	case Unbounded:
+		str += fmt.Sprintf("\nobjective is unbounded along %s", s.Direction)
+	default:
+		if len(s.Optimal) == 1 {
+			str += fmt.Sprintf("\noptimal vertex %s", s.Optimal[0])
+		} else {
+			str += fmt.Sprintf("\noptimal edge %s - %s", s.Optimal[0], s.Optimal[1])
+		}
+		if s.Direction != nil {
+			str += fmt.Sprintf(", optimal along the ray %s", s.Direction)
+		}
+		str += fmt.Sprintf("\nvalue = %s", s.Value)
+	}
+	return str
+}
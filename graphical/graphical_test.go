@@ -0,0 +1,91 @@
+package graphical
+
+import (
+	"bytes"
+	"kw-algos/simplex"
+	"strings"
+	"testing"
+)
+
+// problem строит задачу графического метода по алгебраической записи
+func problem(t *testing.T, source string) *Problem {
+	t.Helper()
+	table, err := simplex.ScanAlgebraic(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(table)
+	if err != nil {
+		t.Fatalf("%q: %s", source, err)
+	}
+	return p
+}
+
+// TestSolve проверяет исход, оптимальное значение, оптимальные вершины и
+// направление луча для ограниченных, неограниченных и несовместных задач
+func TestSolve(t *testing.T) {
+	for _, c := range []struct {
+		source    string
+		status    Status
+		value     string
+		vertices  int
+		optimal   string
+		direction string
+	}{
+		{"max: 3x1 + 2x2;\nx1 + x2 <= 4;\nx1 + 3x2 <= 6;\n", Optimal, "12", 4, "(4; 0)", ""},
+		{"max: x1 + x2 + 1;\nx1 + x2 <= 4;\nx1 <= 3;\n", Optimal, "5", 4, "(3; 1) (0; 4)", ""},
+		{"min: x1 + x2;\nx1 + x2 >= 2;\n", Optimal, "2", 2, "(2; 0) (0; 2)", ""},
+		{"min: 0x1 + x2;\nx1 + x2 >= 2;\n", Optimal, "0", 2, "(2; 0)", "(1; 0)"},
+		{"max: x1 + x2;\nx1 - x2 <= 1;\n", Unbounded, "", 2, "", "(1; 1)"},
+		{"max: x1 + x2;\nx1 + x2 <= 1;\nx1 + x2 >= 3;\n", Infeasible, "", 0, "", ""},
+	} {
+		s := problem(t, c.source).Solve()
+		var optimal []string
+		for _, v := range s.Optimal {
+			optimal = append(optimal, v.String())
+		}
+		direction := ""
+		if s.Direction != nil {
+			direction = s.Direction.String()
+		}
+		value := ""
+		if s.Value != nil {
+			value = s.Value.String()
+		}
+		if s.Status != c.status || value != c.value || len(s.Vertices) != c.vertices ||
+			strings.Join(optimal, " ") != c.optimal || direction != c.direction {
+			t.Errorf("%q: got %s, value %q, vertices %v, optimal %v, direction %q", c.source, s.Status, value, s.Vertices, optimal, direction)
+		}
+	}
+}
+
+// TestNewErrors проверяет, что задача не с двумя переменными отклоняется
+func TestNewErrors(t *testing.T) {
+	for _, source := range []string{"max: x1;\nx1 <= 1;\n", "max: x1 + x2 + x3;\nx1 + x2 + x3 <= 1;\n"} {
+		table, err := simplex.ScanAlgebraic(strings.NewReader(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := New(table); err == nil {
+			t.Errorf("%q: expected an error", source)
+		}
+	}
+}
+
+// TestWriteSVG проверяет, что рисунок строится для каждого исхода
+func TestWriteSVG(t *testing.T) {
+	for _, source := range []string{
+		"max: 3x1 + 2x2;\nx1 + x2 <= 4;\nx1 + 3x2 <= 6;\n",
+		"max: x1 + x2;\nx1 - x2 <= 1;\n",
+		"max: x1 + x2;\nx1 + x2 <= 1;\nx1 + x2 >= 3;\n",
+	} {
+		p := problem(t, source)
+		var svg bytes.Buffer
+		if err := p.WriteSVG(&svg, p.Solve()); err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		if s := svg.String(); !strings.HasPrefix(s, "<svg") || !strings.HasSuffix(strings.TrimSpace(s), "</svg>") {
+			t.Errorf("%q: unexpected SVG:\n%s", source, s)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package graphical
+
+import (
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"math"
+	"strings"
+)
+
+const (
+	svgSize   = 600
+	svgMargin = 50
+)
+
+// canvas переводит координаты задачи в координаты рисунка: область [0, width] x [0, height]
+type canvas struct {
+	width, height float64
+}
+
+func (c canvas) x(v float64) float64 {
+	return svgMargin + v/c.width*(svgSize-2*svgMargin)
+}
+
+func (c canvas) y(v float64) float64 {
+	return svgSize - svgMargin - v/c.height*(svgSize-2*svgMargin)
+}
+
+// box подбирает целочисленные границы рисунка так, чтобы в него попали все
+// вершины, точки пересечения ограничений с осями и часть неограниченной области
+func (p *Problem) box(s *Solution) (int64, int64) {
+	width, height := 1.0, 1.0
+	for _, v := range s.Vertices {
+		width = math.Max(width, v.X.Float64())
+		height = math.Max(height, v.Y.Float64())
+	}
+	for _, c := range p.Constraints {
+		if a := c.A.Float64(); a != 0 && c.RHS.Float64()/a > 0 {
+			width = math.Max(width, c.RHS.Float64()/a)
+		}
+		if b := c.B.Float64(); b != 0 && c.RHS.Float64()/b > 0 {
+			height = math.Max(height, c.RHS.Float64()/b)
+		}
+	}
+	if len(s.Rays) > 0 {
+		width, height = width*1.5, height*1.5
+	}
+	return int64(math.Ceil(width * 1.2)), int64(math.Ceil(height * 1.2))
+}
+
+// WriteSVG рисует ограничения, допустимую область, градиент целевой функции,
+// линию уровня через оптимум и оптимальную вершину или ребро
+func (p *Problem) WriteSVG(w io.Writer, s *Solution) error {
+	width, height := p.box(s)
+	c := canvas{float64(width), float64(height)}
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"12\">\n", svgSize, svgSize)
+	fmt.Fprintf(&b, "<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", svgSize, svgSize)
+
+	// Допустимая область, обрезанная рамкой рисунка
+	if s.Status != Infeasible {
+		right, _ := fractional.New(width, 1)
+		top, _ := fractional.New(height, 1)
+		clipped := append(p.bounds(),
+			Constraint{fractional.OneValue, fractional.ZeroValue, right, simplex.LessThanOrEqualTo},
+			Constraint{fractional.ZeroValue, fractional.OneValue, top, simplex.LessThanOrEqualTo})
+		var points []string
+		for _, v := range vertices(clipped) {
+			points = append(points, fmt.Sprintf("%.2f,%.2f", c.x(v.X.Float64()), c.y(v.Y.Float64())))
+		}
+		fmt.Fprintf(&b, "<polygon points=\"%s\" fill=\"#cfe8ff\" stroke=\"none\"/>\n", strings.Join(points, " "))
+	}
+
+	// Оси с делениями
+	fmt.Fprintf(&b, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"black\"/>\n", c.x(0), c.y(0), c.x(c.width), c.y(0))
+	fmt.Fprintf(&b, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"black\"/>\n", c.x(0), c.y(0), c.x(0), c.y(c.height))
+	fmt.Fprintf(&b, "<text x=\"%.2f\" y=\"%.2f\">x1</text>\n", c.x(c.width)+5, c.y(0)+4)
+	fmt.Fprintf(&b, "<text x=\"%.2f\" y=\"%.2f\">x2</text>\n", c.x(0)-8, c.y(c.height)-8)
+	for _, tick := range ticks(width) {
+		fmt.Fprintf(&b, "<text x=\"%.2f\" y=\"%.2f\" text-anchor=\"middle\">%d</text>\n", c.x(float64(tick)), c.y(0)+16, tick)
+	}
+	for _, tick := range ticks(height) {
+		fmt.Fprintf(&b, "<text x=\"%.2f\" y=\"%.2f\" text-anchor=\"end\">%d</text>\n", c.x(0)-6, c.y(float64(tick))+4, tick)
+	}
+
+	// Граничные прямые ограничений с подписями
+	for i, constraint := range p.Constraints {
+		ends := clip(constraint, c.width, c.height)
+		if len(ends) < 2 {
+			continue
+		}
+		fmt.Fprintf(&b, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"#1f5fa8\" stroke-width=\"1.5\"/>\n",
+			c.x(ends[0][0]), c.y(ends[0][1]), c.x(ends[1][0]), c.y(ends[1][1]))
+		fmt.Fprintf(&b, "<text x=\"%.2f\" y=\"%.2f\" fill=\"#1f5fa8\">(%d) %s</text>\n",
+			c.x(ends[1][0])+4, c.y(ends[1][1])-4, i+1, escape(constraint.String()))
+	}
+
+	// Градиент целевой функции из центра рисунка
+	gx, gy := p.Gradient.X.Float64()/c.width, p.Gradient.Y.Float64()/c.height
+	if length := math.Hypot(gx, gy); length > 0 {
+		const span = svgSize - 2*svgMargin
+		x0, y0 := c.x(c.width/2), c.y(c.height/2)
+		x1, y1 := x0+gx/length*0.15*span, y0-gy/length*0.15*span
+		fmt.Fprintf(&b, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"#c0392b\" stroke-width=\"2\"/>\n", x0, y0, x1, y1)
+		fmt.Fprintf(&b, "<circle cx=\"%.2f\" cy=\"%.2f\" r=\"3\" fill=\"#c0392b\"/>\n", x1, y1)
+		fmt.Fprintf(&b, "<text x=\"%.2f\" y=\"%.2f\" fill=\"#c0392b\">grad = %s</text>\n", x1+4, y1-4, p.Gradient)
+	}
+
+	if s.Status == Optimal {
+		// Линия уровня через оптимум
+		level := Constraint{
+			A:   p.Gradient.X,
+			B:   p.Gradient.Y,
+			RHS: s.Value.Subtract(*p.Constant),
+		}
+		if ends := clip(level, c.width, c.height); len(ends) == 2 {
+			fmt.Fprintf(&b, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"#c0392b\" stroke-dasharray=\"6 4\"/>\n",
+				c.x(ends[0][0]), c.y(ends[0][1]), c.x(ends[1][0]), c.y(ends[1][1]))
+		}
+		if len(s.Optimal) == 2 {
+			fmt.Fprintf(&b, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"#c0392b\" stroke-width=\"4\"/>\n",
+				c.x(s.Optimal[0].X.Float64()), c.y(s.Optimal[0].Y.Float64()), c.x(s.Optimal[1].X.Float64()), c.y(s.Optimal[1].Y.Float64()))
+		}
+		for _, v := range s.Optimal {
+			fmt.Fprintf(&b, "<circle cx=\"%.2f\" cy=\"%.2f\" r=\"5\" fill=\"#c0392b\"/>\n", c.x(v.X.Float64()), c.y(v.Y.Float64()))
+			fmt.Fprintf(&b, "<text x=\"%.2f\" y=\"%.2f\" fill=\"#c0392b\">%s</text>\n", c.x(v.X.Float64())+8, c.y(v.Y.Float64())+14, v)
+		}
+	}
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// clip возвращает концы отрезка граничной прямой ограничения внутри рамки [0, width] x [0, height]
+func clip(c Constraint, width, height float64) [][2]float64 {
+	a, bb, rhs := c.A.Float64(), c.B.Float64(), c.RHS.Float64()
+	var ends [][2]float64
+	add := func(x, y float64) {
+		const eps = 1e-9
+		if x < -eps || x > width+eps || y < -eps || y > height+eps {
+			return
+		}
+		for _, e := range ends {
+			if math.Abs(e[0]-x) < eps && math.Abs(e[1]-y) < eps {
+				return
+			}
+		}
+		ends = append(ends, [2]float64{x, y})
+	}
+	if bb != 0 {
+		add(0, rhs/bb)
+		add(width, (rhs-a*width)/bb)
+	}
+	if a != 0 {
+		add(rhs/a, 0)
+		add((rhs-bb*height)/a, height)
+	}
+	if len(ends) > 2 {
+		ends = ends[:2]
+	}
+	return ends
+}
+
+// ticks выбирает не больше десяти делений оси
+func ticks(limit int64) []int64 {
+	step := max(1, limit/10)
+	var result []int64
+	for v := step; v <= limit; v += step {
+		result = append(result, v)
+	}
+	return result
+}
+
+func escape(s string) string {
+	return strings.NewReplacer("<", "&lt;", ">", "&gt;", "&", "&amp;").Replace(s)
+}
@@ -1,45 +1,643 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"kw-algos/fractional"
+	"kw-algos/graphical"
 	"kw-algos/simplex"
+	"kw-algos/verify"
 	"os"
+	"strings"
 )
 
+// Коды завершения программы
+const (
+	exitOK = iota
+	// exitFailure - у задачи нет решения или ответы не совпали с ожидаемыми
+	exitFailure
+	// exitUsage - неверные аргументы или входной файл
+	exitUsage
+)
+
+type command struct {
+	name, usage string
+	run         func(args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"solve", "solve a problem and print every step", runSolve},
+		{"canonical", "print the canonical form", runCanonical},
+		{"basis", "find the initial basis by the Jordan-Gauss method", runBasis},
+		{"dual", "run the dual simplex method from the initial basis", runDual},
+		{"step", "solve step by step choosing every pivot", runStep},
+		{"check", "grade a hand-computed simplex iteration", runCheck},
+		{"verify", "check problems against their //no, //inf, //<value> annotations", runVerify},
+		{"convert", "convert a problem between input formats", runConvert},
+		{"render", "plot a two-variable problem as SVG", runRender},
+		{"gen", "generate random problems with a requested outcome", runGen},
+	}
+}
+
 func main() {
-	var path string
-	flag.StringVar(&path, "p", "test.txt", "(path to file) <filename>.txt")
-	flag.Parse()
+	args := os.Args[1:]
+	// Прежний вызов с одними флагами (kw-algos -p test.txt) означает solve
+	if len(args) > 0 && strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "-help" {
+		os.Exit(runSolve(args))
+	}
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage()
+		if len(args) == 0 {
+			os.Exit(exitUsage)
+		}
+		return
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			os.Exit(c.run(args[1:]))
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", args[0])
+	usage()
+	os.Exit(exitUsage)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: kw-algos <command> [flags] [file]")
+	fmt.Fprintln(os.Stderr, "\nThe problem is read from the file or, if it is omitted or \"-\", from standard input.")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun kw-algos <command> -h for the command flags.")
+}
+
+// failf печатает ошибку в стандартный поток ошибок и возвращает код завершения
+func failf(code int, format string, a ...any) int {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+	return code
+}
+
+// input открывает файл или стандартный ввод, если путь пуст или равен "-"
+func input(path string) (io.ReadCloser, error) {
+	if path == "" || path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// readTable считывает задачу в одном из входных форматов
+func readTable(r io.Reader, format string) (*simplex.Table, error) {
+	switch format {
+	case "matrix":
+		return simplex.Scan(r)
+	case "algebraic":
+		return simplex.ScanAlgebraic(r)
+	case "lp":
+		return simplex.ReadLP(r)
+	case "mps":
+		return simplex.ReadMPS(r)
+	default:
+		return nil, fmt.Errorf("unknown input format: %s", format)
+	}
+}
+
+// methodFlags - флаги симплекс-метода, общие для solve и dual
+type methodFlags struct {
+	rule          string
+	maxIterations int
+}
+
+func (f *methodFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.rule, "rule", "dantzig", "(pivot rule) dantzig | bland")
+	fs.IntVar(&f.maxIterations, "max-iter", 0, "maximum number of simplex iterations, 0 - no limit")
+}
 
-	f, err := os.Open(path)
+func (f *methodFlags) method(t *simplex.Table) (*simplex.Method, error) {
+	m := simplex.New(t)
+	switch f.rule {
+	case "dantzig":
+		m.Rule = simplex.Dantzig
+	case "bland":
+		m.Rule = simplex.Bland
+	default:
+		return nil, fmt.Errorf("unknown pivot rule: %s", f.rule)
+	}
+	m.MaxIterations = f.maxIterations
+	return m, nil
+}
+
+// parse разбирает флаги команды и открывает входной файл. При ошибке
+// возвращает код завершения, отличный от exitOK
+func parse(fs *flag.FlagSet, args []string) (io.ReadCloser, int) {
+	if err := fs.Parse(args); err != nil {
+		return nil, exitUsage
+	}
+	if fs.NArg() > 1 {
+		return nil, failf(exitUsage, "expected one input file, got %d", fs.NArg())
+	}
+	r, err := input(fs.Arg(0))
 	if err != nil {
-		panic(err)
+		return nil, failf(exitUsage, "%s", err)
 	}
-	defer func(f *os.File) {
-		_ = f.Close()
-	}(f)
-	var r io.Reader
-	r = f
+	return r, exitOK
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: kw-algos %s [flags] [file]\n", name)
+		fs.PrintDefaults()
+	}
+	return fs
+}
 
-	m, err := simplex.Scan(r)
+func runSolve(args []string) int {
+	fs := newFlagSet("solve")
+	var path, format, scale, task, plan, goal, svg, output string
+	var usePresolve, quiet bool
+	var mf methodFlags
+	fs.StringVar(&path, "p", "", "(path to file) <filename>.txt, same as the file argument")
+	fs.StringVar(&format, "f", "matrix", "(input format) matrix | algebraic | lp | mps")
+	fs.StringVar(&scale, "scale", "", "(matrix scaling) geometric | equilibration")
+	fs.BoolVar(&usePresolve, "presolve", false, "simplify the problem before solving")
+	fs.StringVar(&task, "t", "simplex", "(task) simplex | transport | assignment | games | flow | goal | ratio | graphical | vertices")
+	fs.StringVar(&plan, "plan", "vogel", "(initial transport plan) nw | min | vogel")
+	fs.StringVar(&goal, "goal", "lexicographic", "(goal programming) lexicographic | weighted")
+	fs.StringVar(&svg, "svg", "", "(graphical method) write the plot to <filename>.svg")
+	fs.StringVar(&output, "o", "text", "(output format) text | json")
+	fs.BoolVar(&quiet, "q", false, "print only the answer")
+	mf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 1 || path != "" && fs.NArg() > 0 {
+		return failf(exitUsage, "expected one input file")
+	}
+	if path == "" {
+		path = fs.Arg(0)
+	}
+	r, err := input(path)
 	if err != nil {
-		panic(err)
+		return failf(exitUsage, "%s", err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	if output != "text" && output != "json" {
+		return failf(exitUsage, "unknown output format: %s", output)
+	}
+	if task != "simplex" && output == "json" {
+		return failf(exitUsage, "json output is supported only for the simplex task")
+	}
+
+	var taskErr error
+	switch task {
+	case "assignment":
+		taskErr = solveAssignment(r)
+	case "games":
+		taskErr = solveGame(r)
+	case "flow":
+		taskErr = solveFlow(r)
+	case "goal":
+		taskErr = solveGoals(r, goal)
+	case "ratio":
+		taskErr = solveRatio(r)
+	case "transport":
+		taskErr = solveTransport(r, plan)
+	case "simplex", "graphical", "vertices":
+		t, err := readTable(r, format)
+		if err != nil {
+			return failf(exitUsage, "%s", err)
+		}
+		switch task {
+		case "graphical":
+			taskErr = solveGraphical(t, svg)
+		case "vertices":
+			p, err := t.ToCanonicalForm().Vertices()
+			if err != nil {
+				return failf(exitFailure, "%s", err)
+			}
+			fmt.Println(p)
+		default:
+			return solveSimplex(t, simplexOptions{
+				methodFlags: mf,
+				presolve:    usePresolve,
+				scale:       scale,
+				quiet:       quiet || output == "json",
+				json:        output == "json",
+			})
+		}
+	default:
+		return failf(exitUsage, "unknown task: %s", task)
+	}
+	if taskErr != nil {
+		fmt.Println(taskErr)
+		return exitFailure
+	}
+	return exitOK
+}
+
+type simplexOptions struct {
+	methodFlags
+	presolve, quiet, json bool
+	scale                 string
+}
+
+// answer - ответ задачи для вывода в формате JSON
+type answer struct {
+	Status      string   `json:"status"`
+	Objective   string   `json:"objective,omitempty"`
+	X           []string `json:"x,omitempty"`
+	Names       []string `json:"names,omitempty"`
+	Dual        []string `json:"dual,omitempty"`
+	Farkas      []string `json:"farkas,omitempty"`
+	Ray         []string `json:"ray,omitempty"`
+	Alternative bool     `json:"alternative,omitempty"`
+	minimize    bool
+}
+
+// solveSimplex приводит задачу к канонической форме, находит базис методом
+// Жордана-Гаусса и решает её двойственным симплекс-методом
+func solveSimplex(m *simplex.Table, o simplexOptions) int {
+	stdout := io.Writer(os.Stdout)
+	if o.quiet {
+		stdout = io.Discard
+		m.SetOutput(io.Discard)
+	}
+	// Ответ проверяется по задаче до упрощения и масштабирования
+	original := m.Clone()
+
+	var presolve *simplex.Presolve
+	if o.presolve {
+		var err error
+		presolve, err = m.Presolve()
+		if err != nil {
+			return report(o, nil, err)
+		}
+		fmt.Fprintln(stdout, "Presolve:")
+		for _, reduction := range presolve.Reductions {
+			fmt.Fprintln(stdout, reduction)
+		}
+		m = presolve.Reduced
+		if o.quiet {
+			m.SetOutput(io.Discard)
+		}
+	}
+
+	var scaling *simplex.Scaling
+	switch o.scale {
+	case "":
+	case "geometric":
+		scaling = m.Scale(simplex.GeometricMean, 4)
+	case "equilibration":
+		scaling = m.Scale(simplex.Equilibration, 1)
+	default:
+		return failf(exitUsage, "unknown scaling method: %s", o.scale)
+	}
+	if scaling != nil {
+		fmt.Fprintln(stdout, scaling)
 	}
 
-	fmt.Printf("%s\n", m.ToCanonicalForm())
-	fmt.Println("Jordan Gauss:")
+	fmt.Fprintf(stdout, "%s\n", m.ToCanonicalForm())
+	objective := m.Objective()[:m.Vars]
+	constant := m.ObjectiveConstant()
+	fmt.Fprintln(stdout, "Jordan Gauss:")
 	table, err := m.ToBasis()
 	if err != nil {
-		panic(err)
+		return report(o, nil, err)
 	}
-	fmt.Printf("%s\n", m)
+	fmt.Fprintf(stdout, "%s\n", m)
 
-	fmt.Println("Dual Simplex method:")
-	simplexTable := simplex.New(table)
+	fmt.Fprintln(stdout, "Dual Simplex method:")
+	method, err := o.method(table)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	method.Restore = func(x []*fractional.Fraction) ([]*fractional.Fraction, []string) {
+		x = restoreValues(x, original, scaling, presolve)
+		var names []string
+		if len(original.VarNames) > 0 {
+			for j := range x {
+				names = append(names, original.VarName(j))
+			}
+		}
+		return x, names
+	}
+	solveErr := method.DualMethod()
+	if solveErr != nil && !errors.Is(solveErr, simplex.ErrNoSolutions) {
+		return report(o, nil, solveErr)
+	}
+	if solveErr == nil && presolve != nil {
+		// Упрощённая задача совместна, значит пустой столбец без верхней
+		// границы делает неограниченной и исходную
+		solveErr = presolve.Unbounded()
+	}
+	certificate, err := restoreCertificate(method, scaling, presolve)
+	if err == nil {
+		err = original.Verify(certificate)
+	}
+	if err != nil {
+		return report(o, nil, fmt.Errorf("certificate check failed: %w", err))
+	}
+	if solveErr != nil {
+		code := report(o, &answer{Farkas: formatFractions(certificate.Farkas), Ray: formatFractions(certificate.Ray)}, solveErr)
+		fmt.Fprintf(stdout, "verified %s\n", certificate)
+		return code
+	}
+	fmt.Fprintf(stdout, "verified %s\n", certificate)
+
+	x := method.Values()
+	value := constant
+	for j := range x {
+		value = value.Add(*objective[j].Multiply(*x[j]))
+	}
+	// Сдвинутые и разбитые при чтении границ переменные выражаются через исходные
+	x = original.Unsubstitute(certificate.X)
+	if !o.quiet {
+		return exitOK
+	}
+
+	a := &answer{
+		Status:      "optimal",
+		Objective:   value.String(),
+		Dual:        formatFractions(certificate.Y),
+		Alternative: method.Alternative(),
+		minimize:    original.IsMinimizationProblem,
+	}
+	for j, v := range x {
+		a.X = append(a.X, v.String())
+		if len(original.VarNames) > 0 {
+			a.Names = append(a.Names, original.VarName(j))
+		}
+	}
+	return report(o, a, nil)
+}
+
+// restoreValues переводит значения переменных решённой таблицы в переменные
+// задачи, как она была прочитана: до масштабирования, упрощения и замен границ
+func restoreValues(x []*fractional.Fraction, original *simplex.Table, scaling *simplex.Scaling, presolve *simplex.Presolve) []*fractional.Fraction {
+	if scaling != nil {
+		x = scaling.Unscale(x)
+	}
+	if presolve != nil {
+		x = presolve.Postsolve(x)
+	}
+	return original.Unsubstitute(x)
+}
+
+// restoreCertificate строит доказательство результата DualMethod и переводит
+// его в переменные и ограничения задачи до масштабирования и упрощения
+func restoreCertificate(method *simplex.Method, scaling *simplex.Scaling, presolve *simplex.Presolve) (*simplex.Certificate, error) {
+	certificate, err := method.Certificate()
+	if err != nil {
+		return nil, err
+	}
+	if scaling != nil {
+		certificate = scaling.UnscaleCertificate(certificate)
+	}
+	if presolve != nil {
+		return presolve.PostsolveCertificate(certificate)
+	}
+	return certificate, nil
+}
+
+// report печатает ответ или ошибку решения в выбранном формате
+func report(o simplexOptions, a *answer, err error) int {
+	code := exitOK
+	if err != nil {
+		if a == nil {
+			a = &answer{}
+		}
+		a.Status = strings.TrimSpace(err.Error())
+		code = exitFailure
+	}
+	if o.json {
+		data, _ := json.MarshalIndent(a, "", "  ")
+		fmt.Println(string(data))
+		return code
+	}
+	if err != nil {
+		fmt.Println(err)
+		return code
+	}
+	var s strings.Builder
+	for j, v := range a.X {
+		if j > 0 {
+			s.WriteString("; ")
+		}
+		if len(a.Names) > 0 {
+			s.WriteString(a.Names[j] + "=")
+		}
+		s.WriteString(v)
+	}
+	sign := "max"
+	if a.minimize {
+		sign = "min"
+	}
+	fmt.Printf("Z%s(%s) = %s\n", sign, s.String(), a.Objective)
+	if a.Alternative {
+		fmt.Println("solution is optimal, but not the only one")
+	}
+	return code
+}
+
+func formatFractions(values []*fractional.Fraction) []string {
+	var s []string
+	for _, v := range values {
+		s = append(s, v.String())
+	}
+	return s
+}
+
+func runCanonical(args []string) int {
+	fs := newFlagSet("canonical")
+	format := fs.String("f", "matrix", "(input format) matrix | algebraic | lp | mps")
+	r, code := parse(fs, args)
+	if code != exitOK {
+		return code
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	t, err := readTable(r, *format)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	fmt.Println(t.ToCanonicalForm())
+	return exitOK
+}
+
+func runBasis(args []string) int {
+	fs := newFlagSet("basis")
+	format := fs.String("f", "matrix", "(input format) matrix | algebraic | lp | mps")
+	quiet := fs.Bool("q", false, "print only the resulting table")
+	r, code := parse(fs, args)
+	if code != exitOK {
+		return code
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	t, err := readTable(r, *format)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	t.ToCanonicalForm()
+	if *quiet {
+		t.SetOutput(io.Discard)
+	} else {
+		fmt.Printf("%s\n", t)
+		fmt.Println("Jordan Gauss:")
+	}
+	if _, err := t.ToBasis(); err != nil {
+		fmt.Println(err)
+		return exitFailure
+	}
+	fmt.Println(t)
+	return exitOK
+}
 
-	if err := simplexTable.DualMethod(); err != nil {
+func runDual(args []string) int {
+	fs := newFlagSet("dual")
+	format := fs.String("f", "matrix", "(input format) matrix | algebraic | lp | mps")
+	var mf methodFlags
+	mf.register(fs)
+	r, code := parse(fs, args)
+	if code != exitOK {
+		return code
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	t, err := readTable(r, *format)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	t.SetOutput(io.Discard)
+	t.ToCanonicalForm()
+	table, err := t.ToBasis()
+	if err != nil {
+		fmt.Println(err)
+		return exitFailure
+	}
+	table.SetOutput(os.Stdout)
+	method, err := mf.method(table)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	fmt.Println("Dual Simplex method:")
+	if err := method.DualMethod(); err != nil {
 		fmt.Println(err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// runVerify решает все задачи файлов и сверяет ответы с аннотациями //no, //inf, //<значение>
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: kw-algos verify [file...]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	code := exitOK
+	for _, path := range paths {
+		r, err := input(path)
+		if err != nil {
+			return failf(exitUsage, "%s", err)
+		}
+		cases, err := verify.Scan(r)
+		_ = r.Close()
+		if err != nil {
+			return failf(exitUsage, "%s", err)
+		}
+		if len(paths) > 1 {
+			fmt.Printf("%s:\n", path)
+		}
+		if verify.Run(cases, os.Stdout) > 0 {
+			code = exitFailure
+		}
+	}
+	return code
+}
+
+func runConvert(args []string) int {
+	fs := newFlagSet("convert")
+	from := fs.String("f", "matrix", "(input format) matrix | algebraic | lp | mps")
+	to := fs.String("to", "lp", "(output format) matrix | lp | mps")
+	r, code := parse(fs, args)
+	if code != exitOK {
+		return code
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	t, err := readTable(r, *from)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	switch *to {
+	case "matrix":
+		_, err = t.WriteTo(os.Stdout)
+	case "lp":
+		err = t.WriteLP(os.Stdout)
+	case "mps":
+		err = t.WriteMPS(os.Stdout)
+	default:
+		return failf(exitUsage, "unknown output format: %s", *to)
+	}
+	if err != nil {
+		return failf(exitFailure, "%s", err)
+	}
+	return exitOK
+}
+
+func runRender(args []string) int {
+	fs := newFlagSet("render")
+	format := fs.String("f", "matrix", "(input format) matrix | algebraic | lp | mps")
+	output := fs.String("o", "", "write the SVG to the file instead of standard output")
+	r, code := parse(fs, args)
+	if code != exitOK {
+		return code
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	t, err := readTable(r, *format)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	p, err := graphical.New(t)
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	s := p.Solve()
+	if *output == "" {
+		err = p.WriteSVG(os.Stdout, s)
+	} else {
+		var f *os.File
+		if f, err = os.Create(*output); err == nil {
+			err = errors.Join(p.WriteSVG(f, s), f.Close())
+		}
+	}
+	if err != nil {
+		return failf(exitFailure, "%s", err)
+	}
+	if s.Status == graphical.Infeasible {
+		return exitFailure
 	}
+	return exitOK
 }
@@ -0,0 +1,473 @@
+package network
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"strconv"
+	"strings"
+)
+
+var ErrNegativeCycle = errors.New("network has a cycle of negative cost")
+
+// Arc - дуга сети с пропускной способностью Capacity, стоимостью единицы
+// потока Cost и текущим потоком Flow
+type Arc struct {
+	From, To int
+	Capacity *fractional.Fraction
+	Cost     *fractional.Fraction
+	Flow     *fractional.Fraction
+}
+
+// Network - сеть с вершинами 0..Nodes-1, источником Source и стоком Sink.
+// Amount - требуемая величина потока для задачи о потоке минимальной
+// стоимости, nil означает максимальный поток
+type Network struct {
+	Nodes        int
+	Arcs         []*Arc
+	Source, Sink int
+	Amount       *fractional.Fraction
+}
+
+// Scan считывает сеть: первая строка - число вершин и дуг, затем дуги
+// "from to capacity [cost]", последняя строка - "source sink [amount]".
+// Вершины нумеруются с единицы
+func Scan(r io.Reader) (*Network, error) {
+	scanner := bufio.NewScanner(r)
+	var lines [][]string
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || len(lines[0]) != 2 {
+		return nil, fmt.Errorf("expected header: <nodes> <arcs>")
+	}
+	nodes, err := strconv.Atoi(lines[0][0])
+	if err != nil {
+		return nil, err
+	}
+	arcs, err := strconv.Atoi(lines[0][1])
+	if err != nil {
+		return nil, err
+	}
+	if nodes < 2 || arcs < 0 {
+		return nil, fmt.Errorf("expected at least 2 nodes and a non-negative number of arcs, got %d and %d", nodes, arcs)
+	}
+	if len(lines) != arcs+2 {
+		return nil, fmt.Errorf("expected %d arcs and a source/sink line", arcs)
+	}
+
+	n := New(nodes)
+	node := func(field string) (int, error) {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return 0, err
+		}
+		if v < 1 || v > nodes {
+			return 0, fmt.Errorf("node %d out of range 1..%d", v, nodes)
+		}
+		return v - 1, nil
+	}
+	for k, fields := range lines[1 : arcs+1] {
+		if len(fields) < 3 || len(fields) > 4 {
+			return nil, fmt.Errorf("arc %d: expected <from> <to> <capacity> [cost]", k+1)
+		}
+		from, err := node(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := node(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		capacity, err := fractional.Parse(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		cost := fractional.ZeroValue
+		if len(fields) == 4 {
+			if cost, err = fractional.Parse(fields[3]); err != nil {
+				return nil, err
+			}
+		}
+		if err := n.AddArc(from, to, capacity, cost); err != nil {
+			return nil, err
+		}
+	}
+
+	last := lines[len(lines)-1]
+	if len(last) < 2 || len(last) > 3 {
+		return nil, fmt.Errorf("expected <source> <sink> [amount]")
+	}
+	if n.Source, err = node(last[0]); err != nil {
+		return nil, err
+	}
+	if n.Sink, err = node(last[1]); err != nil {
+		return nil, err
+	}
+	if n.Source == n.Sink {
+		return nil, fmt.Errorf("source and sink must differ")
+	}
+	if len(last) == 3 {
+		if n.Amount, err = fractional.Parse(last[2]); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// New создаёт сеть без дуг; источник - первая вершина, сток - последняя
+func New(nodes int) *Network {
+	return &Network{
+		Nodes:  nodes,
+		Source: 0,
+		Sink:   nodes - 1,
+	}
+}
+
+// AddArc добавляет дугу с нулевым потоком
+func (n *Network) AddArc(from, to int, capacity, cost *fractional.Fraction) error {
+	if capacity.LessThan(*fractional.ZeroValue) {
+		return fmt.Errorf("arc (%d, %d) has negative capacity %s", from+1, to+1, capacity)
+	}
+	n.Arcs = append(n.Arcs, &Arc{
+		From:     from,
+		To:       to,
+		Capacity: capacity,
+		Cost:     cost,
+		Flow:     fractional.ZeroValue,
+	})
+	return nil
+}
+
+// residual - дуга остаточной сети: прямая (остаток пропускной способности)
+// или обратная (возможность уменьшить поток по дуге Arc)
+type residual struct {
+	arc       *Arc
+	isReverse bool
+}
+
+func (e residual) from() int {
+	if e.isReverse {
+		return e.arc.To
+	}
+	return e.arc.From
+}
+
+func (e residual) to() int {
+	if e.isReverse {
+		return e.arc.From
+	}
+	return e.arc.To
+}
+
+func (e residual) capacity() *fractional.Fraction {
+	if e.isReverse {
+		return e.arc.Flow
+	}
+	return e.arc.Capacity.Subtract(*e.arc.Flow)
+}
+
+func (e residual) cost() *fractional.Fraction {
+	if e.isReverse {
+		return e.arc.Cost.Reverse()
+	}
+	return e.arc.Cost
+}
+
+func (e residual) push(delta *fractional.Fraction) {
+	if e.isReverse {
+		e.arc.Flow = e.arc.Flow.Subtract(*delta)
+	} else {
+		e.arc.Flow = e.arc.Flow.Add(*delta)
+	}
+}
+
+// residuals возвращает дуги остаточной сети с положительной пропускной способностью
+func (n *Network) residuals() []residual {
+	var edges []residual
+	for _, arc := range n.Arcs {
+		for _, isReverse := range []bool{false, true} {
+			if e := (residual{arc, isReverse}); e.capacity().GreaterThan(*fractional.ZeroValue) {
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
+
+// augment проталкивает по пути наибольший возможный поток, но не больше limit
+func augment(path []residual, limit *fractional.Fraction) *fractional.Fraction {
+	delta := limit
+	for _, e := range path {
+		if delta == nil || e.capacity().LessThan(*delta) {
+			delta = e.capacity()
+		}
+	}
+	for _, e := range path {
+		e.push(delta)
+	}
+	return delta
+}
+
+func (n *Network) pathString(path []residual) string {
+	s := fmt.Sprintf("%d", n.Source+1)
+	for _, e := range path {
+		s += fmt.Sprintf("-%d", e.to()+1)
+	}
+	return s
+}
+
+// reset обнуляет поток по всем дугам
+func (n *Network) reset() {
+	for _, arc := range n.Arcs {
+		arc.Flow = fractional.ZeroValue
+	}
+}
+
+// MaxFlow находит максимальный поток методом Эдмондса-Карпа: на каждом шаге
+// поток увеличивается вдоль кратчайшего по числу дуг пути остаточной сети
+func (n *Network) MaxFlow(w io.Writer) *fractional.Fraction {
+	n.reset()
+	total := fractional.ZeroValue
+	for {
+		path := n.shortestPath()
+		if path == nil {
+			return total
+		}
+		delta := augment(path, nil)
+		total = total.Add(*delta)
+		fmt.Fprintf(w, "path %s, δ = %s, flow = %s\n", n.pathString(path), delta, total)
+	}
+}
+
+// shortestPath ищет путь из источника в сток поиском в ширину
+func (n *Network) shortestPath() []residual {
+	edges := n.residuals()
+	previous := make([]*residual, n.Nodes)
+	visited := make([]bool, n.Nodes)
+	visited[n.Source] = true
+	queue := []int{n.Source}
+	for len(queue) > 0 && !visited[n.Sink] {
+		v := queue[0]
+		queue = queue[1:]
+		for k := range edges {
+			if edges[k].from() == v && !visited[edges[k].to()] {
+				visited[edges[k].to()] = true
+				previous[edges[k].to()] = &edges[k]
+				queue = append(queue, edges[k].to())
+			}
+		}
+	}
+	return n.pathTo(previous)
+}
+
+func (n *Network) pathTo(previous []*residual) []residual {
+	if previous[n.Sink] == nil {
+		return nil
+	}
+	var path []residual
+	for v := n.Sink; v != n.Source; v = previous[v].from() {
+		path = append([]residual{*previous[v]}, path...)
+	}
+	return path
+}
+
+// MinCostFlow находит поток величины Amount (или максимальный, если Amount
+// не задан) минимальной стоимости методом последовательных кратчайших путей:
+// поток увеличивается вдоль самого дешёвого пути остаточной сети, найденного
+// алгоритмом Беллмана-Форда. Возвращает величину и стоимость потока
+func (n *Network) MinCostFlow(w io.Writer) (*fractional.Fraction, *fractional.Fraction, error) {
+	n.reset()
+	total := fractional.ZeroValue
+	for n.Amount == nil || total.LessThan(*n.Amount) {
+		path, err := n.cheapestPath()
+		if err != nil {
+			return nil, nil, err
+		}
+		if path == nil {
+			break
+		}
+		var limit *fractional.Fraction
+		if n.Amount != nil {
+			limit = n.Amount.Subtract(*total)
+		}
+		delta := augment(path, limit)
+		total = total.Add(*delta)
+		cost := fractional.ZeroValue
+		for _, e := range path {
+			cost = cost.Add(*e.cost())
+		}
+		fmt.Fprintf(w, "path %s, cost %s, δ = %s, flow = %s\n", n.pathString(path), cost, delta, total)
+	}
+	if n.Amount != nil && total.LessThan(*n.Amount) {
+		return nil, nil, fmt.Errorf("flow of %s is not feasible, maximum is %s", n.Amount, total)
+	}
+	return total, n.Cost(), nil
+}
+
+// cheapestPath ищет путь наименьшей стоимости из источника в сток
+func (n *Network) cheapestPath() ([]residual, error) {
+	edges := n.residuals()
+	distance := make([]*fractional.Fraction, n.Nodes)
+	previous := make([]*residual, n.Nodes)
+	distance[n.Source] = fractional.ZeroValue
+	for round := 0; ; round++ {
+		updated := false
+		for k, e := range edges {
+			if distance[e.from()] == nil {
+				continue
+			}
+			d := distance[e.from()].Add(*e.cost())
+			if distance[e.to()] == nil || d.LessThan(*distance[e.to()]) {
+				distance[e.to()] = d
+				previous[e.to()] = &edges[k]
+				updated = true
+			}
+		}
+		if !updated {
+			break
+		}
+		if round == n.Nodes {
+			return nil, ErrNegativeCycle
+		}
+	}
+	return n.pathTo(previous), nil
+}
+
+// Value - величина текущего потока: чистый выход из источника
+func (n *Network) Value() *fractional.Fraction {
+	value := fractional.ZeroValue
+	for _, arc := range n.Arcs {
+		if arc.From == n.Source {
+			value = value.Add(*arc.Flow)
+		}
+		if arc.To == n.Source {
+			value = value.Subtract(*arc.Flow)
+		}
+	}
+	return value
+}
+
+// Cost - стоимость текущего потока
+func (n *Network) Cost() *fractional.Fraction {
+	cost := fractional.ZeroValue
+	for _, arc := range n.Arcs {
+		cost = cost.Add(*arc.Flow.Multiply(*arc.Cost))
+	}
+	return cost
+}
+
+// MinCut возвращает вершины, достижимые из источника в остаточной сети.
+// После MaxFlow дуги из этого множества наружу образуют минимальный разрез
+func (n *Network) MinCut() []int {
+	edges := n.residuals()
+	visited := make([]bool, n.Nodes)
+	visited[n.Source] = true
+	queue := []int{n.Source}
+	cut := []int{n.Source}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, e := range edges {
+			if e.from() == v && !visited[e.to()] {
+				visited[e.to()] = true
+				queue = append(queue, e.to())
+				cut = append(cut, e.to())
+			}
+		}
+	}
+	return cut
+}
+
+// ToTable строит задачу линейного программирования о потоке: переменные -
+// потоки по дугам, ограничения - сохранение потока в промежуточных вершинах
+// и пропускные способности. Для Amount == nil максимизируется выход из
+// источника, иначе он фиксируется равным Amount и минимизируется стоимость
+func (n *Network) ToTable() *simplex.Table {
+	vars := len(n.Arcs)
+	var matrix [][]*fractional.Fraction
+	var comparisons []simplex.Comparison
+	var rowNames []string
+	row := func() []*fractional.Fraction {
+		r := make([]*fractional.Fraction, vars+1)
+		for k := range r {
+			r[k] = fractional.ZeroValue
+		}
+		return r
+	}
+	// balance - строка "выход минус вход" вершины v
+	balance := func(v int) []*fractional.Fraction {
+		r := row()
+		for k, arc := range n.Arcs {
+			if arc.From == v {
+				r[k] = r[k].Add(*fractional.OneValue)
+			}
+			if arc.To == v {
+				r[k] = r[k].Subtract(*fractional.OneValue)
+			}
+		}
+		return r
+	}
+
+	for v := range n.Nodes {
+		if v == n.Source || v == n.Sink {
+			continue
+		}
+		matrix = append(matrix, balance(v))
+		comparisons = append(comparisons, simplex.EqualTo)
+		rowNames = append(rowNames, fmt.Sprintf("node%d", v+1))
+	}
+	if n.Amount != nil {
+		r := balance(n.Source)
+		r[vars] = n.Amount
+		matrix = append(matrix, r)
+		comparisons = append(comparisons, simplex.EqualTo)
+		rowNames = append(rowNames, fmt.Sprintf("node%d", n.Source+1))
+	}
+	for k, arc := range n.Arcs {
+		r := row()
+		r[k] = fractional.OneValue
+		r[vars] = arc.Capacity
+		matrix = append(matrix, r)
+		comparisons = append(comparisons, simplex.LessThanOrEqualTo)
+		rowNames = append(rowNames, fmt.Sprintf("cap%d_%d", arc.From+1, arc.To+1))
+	}
+
+	z := balance(n.Source)[:vars]
+	isMinimization := false
+	if n.Amount != nil {
+		z = make([]*fractional.Fraction, vars)
+		for k, arc := range n.Arcs {
+			z[k] = arc.Cost
+		}
+		isMinimization = true
+	}
+	t := simplex.NewTable(matrix, comparisons, z, isMinimization)
+	for _, arc := range n.Arcs {
+		t.VarNames = append(t.VarNames, fmt.Sprintf("f%d_%d", arc.From+1, arc.To+1))
+	}
+	t.RowNames = rowNames
+	return t
+}
+
+func (n *Network) String() string {
+	var s string
+	for _, arc := range n.Arcs {
+		s += fmt.Sprintf(" (%d, %d)%*s%*s/%s", arc.From+1, arc.To+1, 2, "", 6, arc.Flow, arc.Capacity)
+		if arc.Cost.NotEqual(*fractional.ZeroValue) {
+			s += fmt.Sprintf("  cost %s", arc.Cost)
+		}
+		s += "\n"
+	}
+	s += fmt.Sprintf("flow = %s, cost = %s", n.Value(), n.Cost())
+	return s
+}
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"strings"
+	"testing"
+)
+
+// lpOptimum решает задачу ToTable двойственным симплекс-методом и
+// возвращает оптимальное значение её целевой функции
+func lpOptimum(t *testing.T, n *Network) *fractional.Fraction {
+	t.Helper()
+	table := n.ToTable()
+	z := append([]*fractional.Fraction(nil), table.Z...)
+	table.SetOutput(io.Discard)
+	table.ToCanonicalForm()
+	basis, err := table.ToBasis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := simplex.New(basis)
+	m.MaxIterations = 1000
+	if err := m.DualMethod(); err != nil {
+		t.Fatal(err)
+	}
+	value := fractional.ZeroValue
+	for k, x := range m.Values()[:len(z)] {
+		value = value.Add(*z[k].Multiply(*x))
+	}
+	return value
+}
+
+// TestMaxFlow проверяет величину максимального потока, минимальный разрез и
+// совпадение потока с оптимумом задачи линейного программирования
+func TestMaxFlow(t *testing.T) {
+	for _, c := range []struct {
+		source, value, cut string
+	}{
+		{"6 9\n1 2 16\n1 3 13\n2 4 12\n3 2 4\n3 5 14\n4 3 9\n4 6 20\n5 4 7\n5 6 4\n1 6\n", "23", "[1 2 3 5]"},
+		{"4 5\n1 2 3\n1 3 2\n2 3 1\n2 4 2\n3 4 3\n1 4\n", "5", "[1]"},
+		{"3 1\n1 2 5\n1 3\n", "0", "[1 2]"},
+	} {
+		n, err := Scan(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		value := n.MaxFlow(io.Discard)
+		if value.String() != c.value {
+			t.Errorf("%q: flow %s, expected %s", c.source, value, c.value)
+		}
+		var cut []int
+		for _, v := range n.MinCut() {
+			cut = append(cut, v+1)
+		}
+		if s := fmt.Sprint(cut); s != c.cut {
+			t.Errorf("%q: minimum cut %s, expected %s", c.source, s, c.cut)
+		}
+		if lp := lpOptimum(t, n); lp.NotEqual(*value) {
+			t.Errorf("%q: simplex method gives %s, Edmonds-Karp %s", c.source, lp, value)
+		}
+	}
+}
+
+// TestMinCostFlow проверяет стоимость потока заданной величины и её
+// совпадение с оптимумом задачи линейного программирования
+func TestMinCostFlow(t *testing.T) {
+	for _, c := range []struct {
+		source, cost string
+	}{
+		{"4 5\n1 2 4 2\n1 3 2 2\n2 3 2 1\n2 4 3 3\n3 4 5 1\n1 4 5\n", "19"},
+		{"3 3\n1 2 2 1\n2 3 2 1\n1 3 2 3\n1 3 3\n", "7"},
+	} {
+		n, err := Scan(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		value, cost, err := n.MinCostFlow(io.Discard)
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		if value.NotEqual(*n.Amount) || cost.String() != c.cost {
+			t.Errorf("%q: flow %s of cost %s, expected %s of cost %s", c.source, value, cost, n.Amount, c.cost)
+		}
+		if lp := lpOptimum(t, n); lp.NotEqual(*cost) {
+			t.Errorf("%q: simplex method gives %s, successive shortest paths %s", c.source, lp, cost)
+		}
+	}
+
+	n, err := Scan(strings.NewReader("3 2\n1 2 1 1\n2 3 1 1\n1 3 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := n.MinCostFlow(io.Discard); err == nil {
+		t.Error("expected an error for a flow above the maximum")
+	}
+}
+
+// TestScanErrors проверяет, что неверные заголовки, дуги и вершины дают ошибку, а не панику
+func TestScanErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"3\n",
+		"3 -1\n",
+		"3 -1\n1 3\n",
+		"1 0\n1 1\n",
+		"0 0\n1 2\n",
+		"3 1\n1 2 5\n",
+		"3 1\n1 4 5\n1 3\n",
+		"3 1\n1 2\n1 3\n",
+		"3 1\n1 2 5\n2 2\n",
+		"3 1\n1 2 x\n1 3\n",
+	} {
+		if _, err := Scan(strings.NewReader(source)); err == nil {
+			t.Errorf("%q: expected an error", source)
+		}
+	}
+}
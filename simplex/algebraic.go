@@ -0,0 +1,282 @@
+package simplex
+
+import (
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"strings"
+	"unicode"
+)
+
+// ScanAlgebraic считывает задачу в алгебраической записи, где инструкции
+// разделяются точкой с запятой, а комментарии начинаются с "//":
+//
+//	max: 5x + 6y;
+//	c1: 13x + 10y <= 130;
+//	-x + 2y <= 10;
+//
+// Переменные нумеруются в порядке первого появления, их имена и имена
+// ограничений сохраняются в VarNames и RowNames.
+func ScanAlgebraic(r io.Reader) (*Table, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var text strings.Builder
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		text.WriteString(line)
+		text.WriteString("\n")
+	}
+
+	p := &algebraicParser{index: make(map[string]int)}
+	for _, statement := range strings.Split(text.String(), ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if err := p.parseStatement(statement); err != nil {
+			return nil, err
+		}
+	}
+	if p.objective == nil {
+		return nil, fmt.Errorf("objective function (max: or min:) is missing")
+	}
+
+	vars := len(p.names)
+	cols := vars + 1
+	rows := len(p.constraints)
+	matrix := make([][]*fractional.Fraction, rows)
+	comparisons := make([]Comparison, rows)
+	rowNames := make([]string, rows)
+	for i, c := range p.constraints {
+		matrix[i] = make([]*fractional.Fraction, cols, cols*2)
+		for j := range vars {
+			matrix[i][j] = c.coefficient(j)
+		}
+		matrix[i][vars] = c.constant
+		comparisons[i] = c.comparison
+		rowNames[i] = c.name
+	}
+	Z := make([]*fractional.Fraction, vars)
+	for j := range vars {
+		Z[j] = p.objective.coefficient(j)
+	}
+
+	t := &Table{
+		Rows:                  rows,
+		Cols:                  cols,
+		Vars:                  vars,
+		Matrix:                matrix,
+		Z:                     Z,
+		IsMinimizationProblem: p.isMinimization,
+		BasisVars:             make([]int, rows),
+		VarNames:              p.names,
+		RowNames:              rowNames,
+		comparisons:           comparisons,
+	}
+	t.setObjectiveConstant(p.objective.constant)
+	return t, nil
+}
+
+type linearExpression struct {
+	name         string
+	coefficients map[int]*fractional.Fraction
+	constant     *fractional.Fraction
+	comparison   Comparison
+}
+
+func (e *linearExpression) coefficient(index int) *fractional.Fraction {
+	if c, ok := e.coefficients[index]; ok {
+		return c
+	}
+	return fractional.ZeroValue
+}
+
+type algebraicParser struct {
+	names          []string
+	index          map[string]int
+	objective      *linearExpression
+	isMinimization bool
+	constraints    []*linearExpression
+	substitutions  []Substitution
+}
+
+func (p *algebraicParser) parseStatement(statement string) error {
+	var name string
+	body := statement
+	if before, after, ok := strings.Cut(statement, ":"); ok {
+		name = strings.TrimSpace(before)
+		if !isIdentifier(name) {
+			return fmt.Errorf("invalid name %q in %q", name, strings.TrimSpace(statement))
+		}
+		body = after
+	}
+
+	switch strings.ToLower(name) {
+	case "max", "maximize", "min", "minimize":
+		if p.objective != nil {
+			return fmt.Errorf("objective function is defined twice")
+		}
+		expr, err := p.parseExpression(body)
+		if err != nil {
+			return err
+		}
+		p.objective = expr
+		p.isMinimization = strings.HasPrefix(strings.ToLower(name), "min")
+		return nil
+	}
+
+	position := strings.IndexAny(body, "<>=")
+	if position == -1 {
+		return fmt.Errorf("comparison sign is missing in %q", strings.TrimSpace(statement))
+	}
+	width := 1
+	if position+1 < len(body) && strings.ContainsRune("<>=", rune(body[position+1])) {
+		width = 2
+	}
+	sign := body[position : position+width]
+	switch sign {
+	case "=<":
+		sign = "<="
+	case "=>":
+		sign = ">="
+	case "==":
+		sign = "="
+	}
+	comparison, err := parseComparison(sign)
+	if err != nil {
+		return err
+	}
+
+	lhs, err := p.parseExpression(body[:position])
+	if err != nil {
+		return err
+	}
+	rhs, err := p.parseExpression(body[position+width:])
+	if err != nil {
+		return err
+	}
+	// Переносим переменные в левую часть, а константы в правую
+	for j, c := range rhs.coefficients {
+		lhs.coefficients[j] = lhs.coefficient(j).Subtract(*c)
+	}
+	lhs.constant = rhs.constant.Subtract(*lhs.constant)
+	lhs.comparison = comparison
+	lhs.name = name
+	p.constraints = append(p.constraints, lhs)
+	return nil
+}
+
+// parseExpression разбирает линейное выражение вида "3x - 1/2 y + 4*z + 7"
+func (p *algebraicParser) parseExpression(s string) (*linearExpression, error) {
+	expr := &linearExpression{
+		coefficients: make(map[int]*fractional.Fraction),
+		constant:     fractional.ZeroValue,
+	}
+	runes := []rune(s)
+	i := 0
+	skipSpaces := func() {
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+	}
+
+	for first := true; ; first = false {
+		skipSpaces()
+		if i == len(runes) {
+			if first {
+				return nil, fmt.Errorf("empty expression")
+			}
+			return expr, nil
+		}
+
+		sign := fractional.OneValue
+		if runes[i] == '+' || runes[i] == '-' {
+			if runes[i] == '-' {
+				sign = fractional.RevOneValue
+			}
+			i++
+			skipSpaces()
+		} else if !first {
+			return nil, fmt.Errorf("expected + or - before %q in %q", string(runes[i:]), strings.TrimSpace(s))
+		}
+
+		start := i
+		for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == '/') {
+			i++
+		}
+		// Экспонента "1.5e-3" относится к числу, только если за e идёт
+		// порядок; "2e" и "2 e" - коэффициент при переменной e
+		if i > start && i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') && !strings.ContainsRune(string(runes[start:i]), '/') {
+			k := i + 1
+			if k < len(runes) && (runes[k] == '+' || runes[k] == '-') {
+				k++
+			}
+			if k < len(runes) && unicode.IsDigit(runes[k]) {
+				i = k
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+		}
+		value := fractional.OneValue
+		hasNumber := i > start
+		if hasNumber {
+			var err error
+			value, err = parseNumber(string(runes[start:i]))
+			if err != nil {
+				return nil, err
+			}
+			skipSpaces()
+			if i < len(runes) && runes[i] == '*' {
+				i++
+				skipSpaces()
+			}
+		}
+		value = value.Multiply(*sign)
+
+		start = i
+		for i < len(runes) && isIdentifierRune(runes[i], i == start) {
+			i++
+		}
+		if i == start {
+			if !hasNumber {
+				return nil, fmt.Errorf("expected number or variable in %q", strings.TrimSpace(s))
+			}
+			expr.constant = expr.constant.Add(*value)
+			continue
+		}
+		j := p.variable(string(runes[start:i]))
+		expr.coefficients[j] = expr.coefficient(j).Add(*value)
+	}
+}
+
+func (p *algebraicParser) variable(name string) int {
+	if j, ok := p.index[name]; ok {
+		return j
+	}
+	p.index[name] = len(p.names)
+	p.names = append(p.names, name)
+	return len(p.names) - 1
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !isIdentifierRune(r, i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifierRune допускает в именах буквы и "_", а после первого символа
+// также цифры, точки и квадратные скобки, встречающиеся в файлах LP и MPS
+func isIdentifierRune(r rune, first bool) bool {
+	if unicode.IsLetter(r) || r == '_' {
+		return true
+	}
+	return !first && (unicode.IsDigit(r) || strings.ContainsRune(".[]", r))
+}
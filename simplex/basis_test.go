@@ -0,0 +1,96 @@
+package simplex_test
+
+import (
+	"errors"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"strings"
+	"testing"
+)
+
+// TestToBasis проверяет, что после метода Жордана-Гаусса каждая базисная
+// переменная задаётся единичным столбцом своей строки, а базисное решение
+// удовлетворяет системе уравнений канонической формы
+func TestToBasis(t *testing.T) {
+	for _, source := range []string{
+		// Элементы 1 и -1 в столбцах, которые не являются единичными
+		"2 3\n1 1 2 = 4\n1 2 1 = 5\n1 1 1 0 max\n",
+		// Разрешающий элемент равен -1
+		"2 2\n-1 1 = 1\n1/2 3 = 2\n1 1 0 max\n",
+	} {
+		table, err := simplex.Scan(strings.NewReader(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		table.ToCanonicalForm()
+		system := table.CopyMatrix()
+		basis, err := table.ToBasis()
+		if err != nil {
+			t.Fatalf("%s: %s", source, err)
+		}
+		checkBasis(t, source, system, basis)
+	}
+}
+
+func checkBasis(t *testing.T, source string, system [][]*fractional.Fraction, basis *simplex.Table) {
+	t.Helper()
+	n := basis.Cols - 1
+	x := make([]*fractional.Fraction, n)
+	for j := range x {
+		x[j] = fractional.ZeroValue
+	}
+	for i, j := range basis.BasisVars {
+		if j < 0 {
+			continue
+		}
+		for k := range basis.Rows {
+			expected := fractional.ZeroValue
+			if k == i {
+				expected = fractional.OneValue
+			}
+			if basis.Matrix[k][j].NotEqual(*expected) {
+				t.Fatalf("%s: column x%d of the basis variable of row %d is not a unit column:\n%s", source, j+1, i+1, basis)
+			}
+		}
+		x[j] = basis.Matrix[i][n]
+	}
+	for i, row := range system {
+		value := fractional.ZeroValue
+		for j := range n {
+			value = value.Add(*row[j].Multiply(*x[j]))
+		}
+		if value.NotEqual(*row[n]) {
+			t.Fatalf("%s: basic solution %v violates equation %d: %s != %s", source, x, i+1, value, row[n])
+		}
+	}
+}
+
+// TestToBasisMoreRowsThanColumns проверяет системы, в которых столбцы
+// заканчиваются раньше строк: лишние строки либо линейно зависимы, либо противоречивы
+func TestToBasisMoreRowsThanColumns(t *testing.T) {
+	source := "3 1\n2 = 2\n1 = 1\n3 = 3\n1 0 max\n"
+	table, err := simplex.Scan(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.SetOutput(io.Discard)
+	table.ToCanonicalForm()
+	system := table.CopyMatrix()
+	basis, err := table.ToBasis()
+	if err != nil {
+		t.Fatalf("%s: %s", source, err)
+	}
+	checkBasis(t, source, system, basis)
+
+	source = "3 1\n2 = 2\n1 = 2\n3 = 3\n1 0 max\n"
+	table, err = simplex.Scan(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.SetOutput(io.Discard)
+	table.ToCanonicalForm()
+	if _, err := table.ToBasis(); !errors.Is(err, simplex.ErrNoSolutions) {
+		t.Fatalf("%s: expected %q, got %v", source, simplex.ErrNoSolutions, err)
+	}
+}
@@ -0,0 +1,319 @@
+package simplex
+
+import (
+	"errors"
+	"fmt"
+	"kw-algos/fractional"
+)
+
+var ErrNoCertificate = errors.New("the result carries no certificate")
+
+// Certificate - доказательство ответа в терминах исходных ограничений и
+// переменных, которое проверяется без симплекс-таблиц
+type Certificate struct {
+	// X - оптимальная точка, для неограниченной задачи - допустимая точка
+	X []*fractional.Fraction
+	// Y - решение двойственной задачи: для задачи на максимум min b·y при
+	// A^T·y >= c, для задачи на минимум max b·y при A^T·y <= c
+	Y []*fractional.Fraction
+	// Farkas - множители ограничений, сумма которых даёт противоречие
+	Farkas []*fractional.Fraction
+	// Ray - направление из X, вдоль которого целевая функция неограниченно улучшается
+	Ray []*fractional.Fraction
+}
+
+func (c *Certificate) String() string {
+	switch {
+	case c.Farkas != nil:
+		return fmt.Sprintf("infeasible: y = %s", formatVector(c.Farkas))
+	case c.Ray != nil:
+		return fmt.Sprintf("unbounded: x = %s, d = %s", formatVector(c.X), formatVector(c.Ray))
+	default:
+		return fmt.Sprintf("optimal: x = %s, y = %s", formatVector(c.X), formatVector(c.Y))
+	}
+}
+
+// Certificate строит доказательство результата DualMethod. Двойственные
+// оценки находятся из системы B^T·y = c_B по столбцам оптимального базиса
+// исходной матрицы, а не из симплекс-таблицы
+func (m *Method) Certificate() (*Certificate, error) {
+	o := m.Table.original
+	switch {
+	case o == nil:
+		return nil, ErrNoCertificate
+	case m.Farkas != nil:
+		return &Certificate{Farkas: m.Farkas}, nil
+	case m.Ray != nil:
+		return &Certificate{X: m.Values(), Ray: m.Ray}, nil
+	case m.Face == nil:
+		return nil, ErrNoCertificate
+	}
+
+	objective := o.maximizedObjective()
+	y, err := m.basisMultipliers(func(j int) *fractional.Fraction {
+		if j < o.Vars {
+			return objective[j]
+		}
+		return fractional.ZeroValue
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{X: m.Values(), Y: o.fromMaximized(y)}, nil
+}
+
+// farkas находит множители y исходных ограничений, линейная комбинация
+// которых даёт строку row текущей таблицы: y·A = строка, y·b = свободный член.
+// Для строки с отрицательным свободным членом и неотрицательными элементами
+// это доказательство несовместности по лемме Фаркаша
+func (m *Method) farkas(row int) ([]*fractional.Fraction, error) {
+	if m.Table.original == nil {
+		return nil, nil
+	}
+	basic := m.Table.BasisVars[row]
+	return m.basisMultipliers(func(j int) *fractional.Fraction {
+		if j == basic {
+			return fractional.OneValue
+		}
+		return fractional.ZeroValue
+	})
+}
+
+// basisMultipliers решает систему y·A_j = value(j) для всех базисных столбцов j
+// канонической формы исходной задачи: исходные переменные, затем по одной
+// дополнительной переменной на каждое неравенство в порядке строк
+func (m *Method) basisMultipliers(value func(j int) *fractional.Fraction) ([]*fractional.Fraction, error) {
+	o := m.Table.original
+	columns := o.canonicalColumns()
+	system := make([][]*fractional.Fraction, 0, o.Rows)
+	for _, j := range m.Table.BasisVars {
+		if j >= 0 {
+			system = append(system, append(append([]*fractional.Fraction(nil), columns[j]...), value(j)))
+		}
+	}
+	rows, ok := independentRows(system, o.Rows)
+	if !ok {
+		return nil, fmt.Errorf("system y·B = c_B is inconsistent")
+	}
+	y := make([]*fractional.Fraction, o.Rows)
+	for i := range y {
+		y[i] = fractional.ZeroValue
+	}
+	for _, row := range rows {
+		for i := range o.Rows {
+			if row[i].NotEqual(*fractional.ZeroValue) {
+				y[i] = row[o.Rows]
+				break
+			}
+		}
+	}
+	return y, nil
+}
+
+// Verify строит доказательство результата DualMethod и проверяет его по исходной задаче
+func (m *Method) Verify() (*Certificate, error) {
+	c, err := m.Certificate()
+	if err != nil {
+		return nil, err
+	}
+	return c, m.Table.original.Verify(c)
+}
+
+// Verify проверяет доказательство по задаче в исходной постановке, как её читает Scan
+func (t *Table) Verify(c *Certificate) error {
+	switch {
+	case c.Farkas != nil:
+		return t.VerifyInfeasible(c.Farkas)
+	case c.Ray != nil:
+		return t.VerifyUnbounded(c.X, c.Ray)
+	default:
+		return t.VerifyOptimal(c.X, c.Y)
+	}
+}
+
+// VerifyOptimal проверяет допустимость x и двойственную допустимость y и
+// равенство значений прямой и двойственной целевых функций
+func (t *Table) VerifyOptimal(x, y []*fractional.Fraction) error {
+	if err := t.VerifyFeasible(x); err != nil {
+		return err
+	}
+	if len(y) != t.Rows {
+		return fmt.Errorf("dual solution: expected %d values, got %d", t.Rows, len(y))
+	}
+	y = t.fromMaximized(y)
+	if err := t.checkMultipliers(y, "dual solution"); err != nil {
+		return err
+	}
+	objective := t.maximizedObjective()
+	for j := range t.Vars {
+		if value := t.columnValue(j, y); value.LessThan(*objective[j]) {
+			return fmt.Errorf("dual constraint for %s is violated: %s < %s", t.VarName(j), value, objective[j])
+		}
+	}
+	primal, dual := dot(objective, x), dot(t.rhs(), y)
+	if primal.NotEqual(*dual) {
+		return fmt.Errorf("objective values differ: c·x = %s, b·y = %s", primal, dual)
+	}
+	return nil
+}
+
+// VerifyFeasible проверяет неотрицательность x и все исходные ограничения
+func (t *Table) VerifyFeasible(x []*fractional.Fraction) error {
+	if len(x) != t.Vars {
+		return fmt.Errorf("solution: expected %d values, got %d", t.Vars, len(x))
+	}
+	for j, v := range x {
+		if v.LessThan(*fractional.ZeroValue) {
+			return fmt.Errorf("%s = %s is negative", t.VarName(j), v)
+		}
+	}
+	for i := range t.Rows {
+		if !t.comparisons[i].holds(t.rowValue(i, x), t.Matrix[i][t.Cols-1]) {
+			return fmt.Errorf("constraint %d is violated: %s %s %s", i+1, t.rowValue(i, x), &t.comparisons[i], t.Matrix[i][t.Cols-1])
+		}
+	}
+	return nil
+}
+
+// VerifyInfeasible проверяет лемму Фаркаша: множители y допустимого знака
+// (y >= 0 для <=, y <= 0 для >=) дают y·A >= 0 и y·b < 0, поэтому
+// неравенство y·A·x <= y·b не выполняется ни при каком x >= 0
+func (t *Table) VerifyInfeasible(y []*fractional.Fraction) error {
+	if len(y) != t.Rows {
+		return fmt.Errorf("Farkas certificate: expected %d values, got %d", t.Rows, len(y))
+	}
+	if err := t.checkMultipliers(y, "Farkas certificate"); err != nil {
+		return err
+	}
+	for j := range t.Vars {
+		if value := t.columnValue(j, y); value.LessThan(*fractional.ZeroValue) {
+			return fmt.Errorf("Farkas certificate: coefficient of %s is %s < 0", t.VarName(j), value)
+		}
+	}
+	if value := dot(t.rhs(), y); !value.LessThan(*fractional.ZeroValue) {
+		return fmt.Errorf("Farkas certificate: y·b = %s is not negative", value)
+	}
+	return nil
+}
+
+// VerifyUnbounded проверяет допустимость x и то, что луч x + λ·d, λ >= 0,
+// не выходит из допустимого множества и улучшает целевую функцию
+func (t *Table) VerifyUnbounded(x, d []*fractional.Fraction) error {
+	if err := t.VerifyFeasible(x); err != nil {
+		return err
+	}
+	if len(d) != t.Vars {
+		return fmt.Errorf("ray: expected %d values, got %d", t.Vars, len(d))
+	}
+	for j, v := range d {
+		if v.LessThan(*fractional.ZeroValue) {
+			return fmt.Errorf("ray: %s = %s is negative", t.VarName(j), v)
+		}
+	}
+	for i := range t.Rows {
+		if !t.comparisons[i].holds(t.rowValue(i, d), fractional.ZeroValue) {
+			return fmt.Errorf("ray leaves constraint %d: %s %s 0 does not hold", i+1, t.rowValue(i, d), &t.comparisons[i])
+		}
+	}
+	if value := dot(t.maximizedObjective(), d); !value.GreaterThan(*fractional.ZeroValue) {
+		return fmt.Errorf("ray does not improve the objective: c·d = %s", t.objectiveSign().Multiply(*value))
+	}
+	return nil
+}
+
+// checkMultipliers проверяет знаки множителей ограничений для задачи на максимум
+func (t *Table) checkMultipliers(y []*fractional.Fraction, name string) error {
+	for i, v := range y {
+		switch {
+		case t.comparisons[i] == LessThanOrEqualTo && v.LessThan(*fractional.ZeroValue),
+			t.comparisons[i] == GreaterThanOrEqualTo && v.GreaterThan(*fractional.ZeroValue):
+			return fmt.Errorf("%s: y%d = %s has the wrong sign for a %s constraint", name, i+1, v, &t.comparisons[i])
+		}
+	}
+	return nil
+}
+
+func (c *Comparison) holds(value, rhs *fractional.Fraction) bool {
+	switch *c {
+	case LessThanOrEqualTo:
+		return !value.GreaterThan(*rhs)
+	case GreaterThanOrEqualTo:
+		return !value.LessThan(*rhs)
+	default:
+		return value.Equal(*rhs)
+	}
+}
+
+func (t *Table) rowValue(i int, x []*fractional.Fraction) *fractional.Fraction {
+	return dot(t.Matrix[i][:t.Vars], x)
+}
+
+func (t *Table) columnValue(j int, y []*fractional.Fraction) *fractional.Fraction {
+	value := fractional.ZeroValue
+	for i := range t.Rows {
+		value = value.Add(*t.Matrix[i][j].Multiply(*y[i]))
+	}
+	return value
+}
+
+func (t *Table) rhs() []*fractional.Fraction {
+	b := make([]*fractional.Fraction, t.Rows)
+	for i := range b {
+		b[i] = t.Matrix[i][t.Cols-1]
+	}
+	return b
+}
+
+// canonicalColumns возвращает столбцы канонической формы исходной задачи
+func (t *Table) canonicalColumns() [][]*fractional.Fraction {
+	var columns [][]*fractional.Fraction
+	for j := range t.Vars {
+		column := make([]*fractional.Fraction, t.Rows)
+		for i := range t.Rows {
+			column[i] = t.Matrix[i][j]
+		}
+		columns = append(columns, column)
+	}
+	for i, comparison := range t.comparisons {
+		if comparison == EqualTo {
+			continue
+		}
+		column := make([]*fractional.Fraction, t.Rows)
+		for k := range column {
+			column[k] = fractional.ZeroValue
+		}
+		column[i] = fractional.OneValue
+		if comparison == GreaterThanOrEqualTo {
+			column[i] = fractional.RevOneValue
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// objectiveSign равен 1 для задачи на максимум и -1 для задачи на минимум
+func (t *Table) objectiveSign() *fractional.Fraction {
+	if t.IsMinimizationProblem {
+		return fractional.RevOneValue
+	}
+	return fractional.OneValue
+}
+
+// maximizedObjective - коэффициенты целевой функции задачи, сведённой к максимизации
+func (t *Table) maximizedObjective() []*fractional.Fraction {
+	c := make([]*fractional.Fraction, t.Vars)
+	for j := range c {
+		c[j] = t.Z[j].Multiply(*t.objectiveSign())
+	}
+	return c
+}
+
+// fromMaximized переводит двойственные оценки задачи, сведённой к
+// максимизации, в оценки исходной задачи и обратно
+func (t *Table) fromMaximized(y []*fractional.Fraction) []*fractional.Fraction {
+	result := make([]*fractional.Fraction, len(y))
+	for i, v := range y {
+		result[i] = v.Multiply(*t.objectiveSign())
+	}
+	return result
+}
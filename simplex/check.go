@@ -0,0 +1,206 @@
+package simplex
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"strings"
+)
+
+// Mismatch - ячейка симплекс-таблицы, в которой ответ отличается от верного.
+// Row и Col равны -1 для Z-строки и столбца свободных членов
+type Mismatch struct {
+	Row, Col int
+	Label    string
+	Expected string
+	Got      string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s: expected %s, got %s", m.Label, m.Expected, m.Got)
+}
+
+// CheckIteration делает шаг симплекс-метода с разрешающим элементом (row, col)
+// по правилу прямоугольника и сравнивает результат с таблицей claimed:
+// базисные переменные, свободные члены, элементы матрицы, Z-строку и ZFree
+func (t *Table) CheckIteration(row, col int, claimed *Table) ([]Mismatch, error) {
+	if row < 0 || row >= t.Rows || col < 0 || col >= t.Cols-1 {
+		return nil, fmt.Errorf("pivot (%d, %d) is out of the table", row+1, col+1)
+	}
+	if t.Matrix[row][col].Equal(*fractional.ZeroValue) {
+		return nil, ErrZeroPivot
+	}
+	if claimed.Rows != t.Rows || claimed.Cols != t.Cols {
+		return nil, fmt.Errorf("table size: expected %d x %d, got %d x %d", t.Rows, t.Cols-1, claimed.Rows, claimed.Cols-1)
+	}
+	m := &Method{Table: t.clone()}
+	if err := m.pivot(row, col); err != nil {
+		return nil, err
+	}
+	expected := m.Table
+
+	var mismatches []Mismatch
+	add := func(i, j int, label string, e, g *fractional.Fraction) {
+		if e.NotEqual(*g) {
+			mismatches = append(mismatches, Mismatch{i, j, label, e.String(), g.String()})
+		}
+	}
+	for i := range t.Rows {
+		name := expected.VarName(expected.BasisVars[i])
+		if got := claimed.VarName(claimed.BasisVars[i]); got != name {
+			mismatches = append(mismatches, Mismatch{i, -1, fmt.Sprintf("row %d basis variable", i+1), name, got})
+		}
+		add(i, -1, fmt.Sprintf("row %d (%s), free term", i+1, name), expected.Matrix[i][t.Cols-1], claimed.Matrix[i][t.Cols-1])
+		for j := range t.Cols - 1 {
+			add(i, j, fmt.Sprintf("row %d (%s), column %s", i+1, name, expected.VarName(j)), expected.Matrix[i][j], claimed.Matrix[i][j])
+		}
+	}
+	add(-1, -1, "Z, free term", expected.ZFree, claimed.ZFree)
+	for j := range t.Cols - 1 {
+		add(-1, j, fmt.Sprintf("Z, column %s", expected.VarName(j)), expected.Z[j], claimed.Z[j])
+	}
+	return mismatches, nil
+}
+
+// ScanTableau считывает симплекс-таблицу в том виде, в котором её печатает
+// Method.String: заголовок "B.V | 1 | x1 x2 ...", строки "x3 | b | a1 a2 ...",
+// строка "Z | ZFree | z1 z2 ...". Столбец и строка CO пропускаются
+func ScanTableau(r io.Reader) (*Table, error) {
+	scanner := bufio.NewScanner(r)
+	t := &Table{}
+	var basis []string
+	for scanner.Scan() {
+		cells := strings.Split(scanner.Text(), "|")
+		label := strings.TrimSpace(cells[0])
+		if label == "" || label == "CO" {
+			continue
+		}
+		if len(cells) < 3 {
+			return nil, fmt.Errorf("tableau line %q: expected label | free term | coefficients", scanner.Text())
+		}
+		fields := strings.Fields(cells[2])
+		if label == "B.V" {
+			t.VarNames = fields
+			continue
+		}
+		if t.VarNames == nil {
+			return nil, fmt.Errorf("tableau header B.V | 1 | x1 ... is missing")
+		}
+		if len(fields) != len(t.VarNames) {
+			return nil, fmt.Errorf("tableau line %q: expected %d coefficients", scanner.Text(), len(t.VarNames))
+		}
+		values, err := parseFractions(append([]string{strings.TrimSpace(cells[1])}, fields...))
+		if err != nil {
+			return nil, err
+		}
+		if label == "Z" {
+			t.ZFree, t.Z = values[0], values[1:]
+			continue
+		}
+		basis = append(basis, label)
+		t.Matrix = append(t.Matrix, append(values[1:], values[0]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t.withBasis(basis)
+}
+
+// jsonTableau - симплекс-таблица в JSON. Числа записываются строками вида
+// "1/3" или числами
+type jsonTableau struct {
+	Vars   []string            `json:"vars"`
+	Basis  []string            `json:"basis"`
+	RHS    []json.RawMessage   `json:"rhs"`
+	Matrix [][]json.RawMessage `json:"matrix"`
+	Z      []json.RawMessage   `json:"z"`
+	ZFree  json.RawMessage     `json:"zfree"`
+}
+
+// ReadTableauJSON считывает симплекс-таблицу в формате
+// {"vars": [...], "basis": [...], "rhs": [...], "matrix": [[...]], "z": [...], "zfree": ...}
+func ReadTableauJSON(r io.Reader) (*Table, error) {
+	var j jsonTableau
+	if err := json.NewDecoder(r).Decode(&j); err != nil {
+		return nil, err
+	}
+	if len(j.RHS) != len(j.Matrix) || len(j.Basis) != len(j.Matrix) {
+		return nil, fmt.Errorf("tableau: basis, rhs and matrix must have the same number of rows")
+	}
+	t := &Table{VarNames: j.Vars}
+	var err error
+	if t.Z, err = parseRawFractions(j.Z); err != nil {
+		return nil, err
+	}
+	if t.VarNames == nil {
+		for k := range t.Z {
+			t.VarNames = append(t.VarNames, fmt.Sprintf("x%d", k+1))
+		}
+	}
+	t.ZFree = fractional.ZeroValue
+	if len(j.ZFree) > 0 {
+		zFree, err := parseRawFractions([]json.RawMessage{j.ZFree})
+		if err != nil {
+			return nil, err
+		}
+		t.ZFree = zFree[0]
+	}
+	for i, row := range j.Matrix {
+		values, err := parseRawFractions(append(row, j.RHS[i]))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != len(t.Z)+1 {
+			return nil, fmt.Errorf("tableau row %d: expected %d coefficients", i+1, len(t.Z))
+		}
+		t.Matrix = append(t.Matrix, values)
+	}
+	return t.withBasis(j.Basis)
+}
+
+// withBasis заполняет размеры таблицы и номера базисных переменных по их именам
+func (t *Table) withBasis(basis []string) (*Table, error) {
+	if t.Z == nil || len(t.Z) != len(t.VarNames) {
+		return nil, fmt.Errorf("tableau: Z row is missing")
+	}
+	t.Rows, t.Vars, t.Cols = len(t.Matrix), len(t.VarNames), len(t.VarNames)+1
+	t.BasisVars = make([]int, t.Rows)
+	for i, name := range basis {
+		t.BasisVars[i] = -1
+		for j, v := range t.VarNames {
+			if v == name {
+				t.BasisVars[i] = j
+			}
+		}
+		if t.BasisVars[i] == -1 {
+			return nil, fmt.Errorf("tableau: unknown basis variable %s", name)
+		}
+	}
+	t.comparisons = make([]Comparison, t.Rows)
+	return t, nil
+}
+
+func parseFractions(fields []string) ([]*fractional.Fraction, error) {
+	values := make([]*fractional.Fraction, len(fields))
+	for k, f := range fields {
+		var err error
+		if values[k], err = fractional.Parse(f); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func parseRawFractions(raw []json.RawMessage) ([]*fractional.Fraction, error) {
+	fields := make([]string, len(raw))
+	for k, r := range raw {
+		var s string
+		if err := json.Unmarshal(r, &s); err != nil {
+			s = string(r)
+		}
+		fields[k] = s
+	}
+	return parseFractions(fields)
+}
@@ -0,0 +1,219 @@
+package simplex
+
+import (
+	"fmt"
+	"kw-algos/fractional"
+	"sort"
+	"strings"
+)
+
+// maxOptimalBases ограничивает перебор оптимальных базисов в сильно вырожденных задачах
+const maxOptimalBases = 1000
+
+// ErrFaceTruncated - перебор оптимальных базисов остановлен, множество оптимальных решений может быть неполным
+var ErrFaceTruncated = fmt.Errorf("optimal set search stopped after %d bases", maxOptimalBases)
+
+// OptimalFace - множество оптимальных решений: выпуклая оболочка вершин
+// Vertices плюс неотрицательные комбинации направлений Rays
+type OptimalFace struct {
+	Vertices [][]*fractional.Fraction
+	Rays     [][]*fractional.Fraction
+	// Truncated - перебор остановлен на maxOptimalBases базисах, часть вершин и лучей может отсутствовать
+	Truncated bool
+}
+
+// optimalFace обходит все оптимальные базисы, начиная с текущей оптимальной
+// таблицы: из каждого базиса вводится каждая небазисная переменная с нулевой
+// оценкой по каждой строке с минимальным симплексным отношением. Базисы не
+// посещаются повторно. Столбец с нулевой оценкой без положительных элементов
+// задаёт направление, вдоль которого оптимальное множество неограничено
+func (m *Method) optimalFace() (*OptimalFace, error) {
+	face := &OptimalFace{}
+	visited := map[string]bool{basisKey(m.Table.BasisVars): true}
+	queue := []*Table{m.Table.clone()}
+
+	for len(queue) > 0 && len(visited) <= maxOptimalBases {
+		current := &Method{Table: queue[0]}
+		queue = queue[1:]
+		face.addVertex(current.Values())
+
+		for j, z := range current.Table.Z {
+			if _, ok := current.Table.IsContainedInBasis(j); ok || z.NotEqual(*fractional.ZeroValue) {
+				continue
+			}
+			rows, err := current.ratioRows(j)
+			if err != nil {
+				return nil, err
+			}
+			if len(rows) == 0 {
+				face.addRay(current.direction(j))
+				continue
+			}
+			for _, row := range rows {
+				next := &Method{Table: current.Table.clone()}
+				if err := next.pivot(row, j); err != nil {
+					return nil, err
+				}
+				key := basisKey(next.Table.BasisVars)
+				if !visited[key] {
+					visited[key] = true
+					queue = append(queue, next.Table)
+				}
+			}
+		}
+	}
+	face.Truncated = len(queue) > 0
+	return face, nil
+}
+
+// Alternative сообщает, что оптимальное множество содержит больше одной точки
+// в переменных, которые возвращает Restore: вершины переводятся в них
+// непосредственно, луч d - как разность образов x + d и x первой вершины x
+func (m *Method) Alternative() bool {
+	if m.Face == nil || len(m.Face.Vertices) == 0 {
+		return false
+	}
+	restore := func(x []*fractional.Fraction) []*fractional.Fraction {
+		if m.Restore == nil {
+			return x
+		}
+		x, _ = m.Restore(x)
+		return x
+	}
+	first := m.Face.Vertices[0]
+	points := [][]*fractional.Fraction{restore(first)}
+	for _, vertex := range m.Face.Vertices[1:] {
+		points = append(points, restore(vertex))
+	}
+	for _, d := range m.Face.Rays {
+		shifted := make([]*fractional.Fraction, len(first))
+		for j := range first {
+			shifted[j] = first[j].Add(*d[j])
+		}
+		points = append(points, restore(shifted))
+	}
+	for _, x := range points[1:] {
+		if !equalVectors(x, points[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// ratioRows возвращает строки с минимальным отношением свободного члена к
+// положительному элементу столбца j
+func (m *Method) ratioRows(j int) ([]int, error) {
+	var rows []int
+	var best *fractional.Fraction
+	for i := range m.Table.Rows {
+		if !m.Table.Matrix[i][j].GreaterThan(*fractional.ZeroValue) {
+			continue
+		}
+		ratio, err := m.Table.Matrix[i][m.Table.Cols-1].Divide(*m.Table.Matrix[i][j])
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case best == nil || ratio.LessThan(*best):
+			best = ratio
+			rows = []int{i}
+		case ratio.Equal(*best):
+			rows = append(rows, i)
+		}
+	}
+	return rows, nil
+}
+
+// direction - изменение исходных переменных при единичном увеличении небазисной переменной j
+func (m *Method) direction(j int) []*fractional.Fraction {
+	d := make([]*fractional.Fraction, m.Table.Vars)
+	for i := range d {
+		d[i] = fractional.ZeroValue
+		if i == j {
+			d[i] = fractional.OneValue
+		} else if row, ok := m.Table.IsContainedInBasis(i); ok {
+			d[i] = m.Table.Matrix[row][j].Reverse()
+		}
+	}
+	return d
+}
+
+func (f *OptimalFace) addVertex(x []*fractional.Fraction) {
+	if !containsVector(f.Vertices, x) {
+		f.Vertices = append(f.Vertices, x)
+	}
+}
+
+func (f *OptimalFace) addRay(d []*fractional.Fraction) {
+	if !containsVector(f.Rays, d) {
+		f.Rays = append(f.Rays, d)
+	}
+}
+
+func containsVector(vectors [][]*fractional.Fraction, v []*fractional.Fraction) bool {
+	for _, u := range vectors {
+		if equalVectors(u, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func basisKey(basis []int) string {
+	sorted := append([]int(nil), basis...)
+	sort.Ints(sorted)
+	return fmt.Sprint(sorted)
+}
+
+// clone копирует таблицу вместе с базисом
+func (t *Table) clone() *Table {
+	c := *t
+	c.Matrix = t.CopyMatrix()
+	c.Z = t.CopyZ()
+	c.ZFree = t.CopyZFree()
+	c.BasisVars = t.CopyBasisVars()
+	c.comparisons = append([]Comparison(nil), t.comparisons...)
+	return &c
+}
+
+func formatVector(v []*fractional.Fraction) string {
+	parts := make([]string, len(v))
+	for i, x := range v {
+		parts[i] = x.String()
+	}
+	return "(" + strings.Join(parts, "; ") + ")"
+}
+
+func (f *OptimalFace) String() string {
+	var s string
+	var terms, conditions []string
+	for k, v := range f.Vertices {
+		s += fmt.Sprintf("x(%d) = %s\n", k+1, formatVector(v))
+		if len(f.Vertices) == 1 {
+			terms = append(terms, "x(1)")
+			continue
+		}
+		terms = append(terms, fmt.Sprintf("λ%d·x(%d)", k+1, k+1))
+		conditions = append(conditions, fmt.Sprintf("λ%d", k+1))
+	}
+	for k, d := range f.Rays {
+		s += fmt.Sprintf("d(%d) = %s\n", k+1, formatVector(d))
+		terms = append(terms, fmt.Sprintf("μ%d·d(%d)", k+1, k+1))
+	}
+	s += fmt.Sprintf("x^(*) = %s", strings.Join(terms, " + "))
+	if len(f.Vertices) > 1 {
+		s += fmt.Sprintf(", %s = 1", strings.Join(conditions, " + "))
+	}
+	switch {
+	case len(f.Vertices) > 1 && len(f.Rays) > 0:
+		s += ", λ, μ >= 0"
+	case len(f.Vertices) > 1:
+		s += ", λ >= 0"
+	case len(f.Rays) > 0:
+		s += ", μ >= 0"
+	}
+	if f.Truncated {
+		s += fmt.Sprintf("\n%s, the set may be incomplete", ErrFaceTruncated)
+	}
+	return s
+}
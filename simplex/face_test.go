@@ -0,0 +1,46 @@
+package simplex
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestOptimalFaceTruncated проверяет, что перебор оптимальных базисов куба
+// с нулевой целевой функцией отмечается неполным, когда вершин больше maxOptimalBases
+func TestOptimalFaceTruncated(t *testing.T) {
+	for _, c := range []struct {
+		vars      int
+		truncated bool
+	}{
+		{9, false},
+		{10, true},
+	} {
+		var source strings.Builder
+		source.WriteString("max: 0x1;\n")
+		for j := range c.vars {
+			fmt.Fprintf(&source, "x%d <= 1;\n", j+1)
+		}
+		table, err := ScanAlgebraic(strings.NewReader(source.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		table.SetOutput(io.Discard)
+		table.ToCanonicalForm()
+		basis, err := table.ToBasis()
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := New(basis)
+		if err := m.DualMethod(); err != nil {
+			t.Fatal(err)
+		}
+		if m.Face.Truncated != c.truncated {
+			t.Errorf("%d variables: truncated %t with %d vertices, expected %t", c.vars, m.Face.Truncated, len(m.Face.Vertices), c.truncated)
+		}
+		if !c.truncated && len(m.Face.Vertices) != 1<<c.vars {
+			t.Errorf("%d variables: %d vertices, expected %d", c.vars, len(m.Face.Vertices), 1<<c.vars)
+		}
+	}
+}
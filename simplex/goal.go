@@ -0,0 +1,340 @@
+package simplex
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Goal - цель задачи целевого программирования: Σ Coefficients·x сравнивается
+// с Target. Для >= штрафуется недовыполнение, для <= - перевыполнение, для = -
+// оба отклонения. Priority - уровень приоритета (1 - высший), Weight - вес
+// отклонения внутри уровня
+type Goal struct {
+	Coefficients []*fractional.Fraction
+	Comparison   Comparison
+	Target       *fractional.Fraction
+	Priority     int
+	Weight       *fractional.Fraction
+}
+
+// GoalProgram - задача целевого программирования: жёсткие ограничения Table
+// (без целевой функции) и цели Goals
+type GoalProgram struct {
+	Table *Table
+	Goals []Goal
+}
+
+// GoalResult - решение задачи: значения переменных X, недовыполнение Under и
+// перевыполнение Over каждой цели и достигнутые значения штрафа по уровням приоритета
+type GoalResult struct {
+	X           []*fractional.Fraction
+	Under, Over []*fractional.Fraction
+	Priorities  []int
+	Penalties   []*fractional.Fraction
+}
+
+// ScanGoals считывает задачу: первая строка - число ограничений, переменных
+// и целей, затем ограничения в формате Scan, затем цели
+// "c1 ... cn <знак> target [priority [weight]]" (по умолчанию 1 и 1)
+func ScanGoals(r io.Reader) (*GoalProgram, error) {
+	scanner := bufio.NewScanner(r)
+	var lines [][]string
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || len(lines[0]) != 3 {
+		return nil, fmt.Errorf("expected header: <rows> <vars> <goals>")
+	}
+	var header [3]int
+	for k := range header {
+		var err error
+		if header[k], err = strconv.Atoi(lines[0][k]); err != nil {
+			return nil, err
+		}
+	}
+	rows, vars, goals := header[0], header[1], header[2]
+	if rows < 0 || vars < 1 || goals < 1 {
+		return nil, fmt.Errorf("expected a non-negative number of constraints and positive numbers of variables and goals, got %d, %d and %d", rows, vars, goals)
+	}
+	if len(lines) != 1+rows+goals {
+		return nil, fmt.Errorf("expected %d constraints and %d goals", rows, goals)
+	}
+
+	// parseRow разбирает коэффициенты, знак и правую часть, возвращая остаток строки
+	parseRow := func(fields []string) ([]*fractional.Fraction, Comparison, []string, error) {
+		if len(fields) < vars+2 {
+			return nil, 0, nil, fmt.Errorf("expected %d coefficients, a comparison and a right-hand side", vars)
+		}
+		row := make([]*fractional.Fraction, vars+1)
+		for j := range vars {
+			var err error
+			if row[j], err = fractional.Parse(fields[j]); err != nil {
+				return nil, 0, nil, err
+			}
+		}
+		comparison, err := parseComparison(fields[vars])
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if row[vars], err = fractional.Parse(fields[vars+1]); err != nil {
+			return nil, 0, nil, err
+		}
+		return row, comparison, fields[vars+2:], nil
+	}
+
+	matrix := make([][]*fractional.Fraction, rows)
+	comparisons := make([]Comparison, rows)
+	for i := range rows {
+		var rest []string
+		var err error
+		matrix[i], comparisons[i], rest, err = parseRow(lines[1+i])
+		if err != nil {
+			return nil, fmt.Errorf("constraint %d: %w", i+1, err)
+		}
+		if len(rest) != 0 {
+			return nil, fmt.Errorf("constraint %d: unexpected %q", i+1, rest[0])
+		}
+	}
+	z := make([]*fractional.Fraction, vars)
+	for j := range z {
+		z[j] = fractional.ZeroValue
+	}
+	gp := &GoalProgram{Table: NewTable(matrix, comparisons, z, true)}
+
+	for k := range goals {
+		row, comparison, rest, err := parseRow(lines[1+rows+k])
+		if err != nil {
+			return nil, fmt.Errorf("goal %d: %w", k+1, err)
+		}
+		goal := Goal{
+			Coefficients: row[:vars],
+			Comparison:   comparison,
+			Target:       row[vars],
+			Priority:     1,
+			Weight:       fractional.OneValue,
+		}
+		if len(rest) > 2 {
+			return nil, fmt.Errorf("goal %d: unexpected %q", k+1, rest[2])
+		}
+		if len(rest) > 0 {
+			if goal.Priority, err = strconv.Atoi(rest[0]); err != nil {
+				return nil, fmt.Errorf("goal %d: %w", k+1, err)
+			}
+			// Приоритет 0 обозначает в GoalResult взвешенное решение
+			if goal.Priority < 1 {
+				return nil, fmt.Errorf("goal %d: expected a positive priority, got %d", k+1, goal.Priority)
+			}
+		}
+		if len(rest) > 1 {
+			if goal.Weight, err = fractional.Parse(rest[1]); err != nil {
+				return nil, fmt.Errorf("goal %d: %w", k+1, err)
+			}
+		}
+		gp.Goals = append(gp.Goals, goal)
+	}
+	return gp, nil
+}
+
+// Priorities возвращает уровни приоритета целей по возрастанию
+func (gp *GoalProgram) Priorities() []int {
+	var priorities []int
+	for _, goal := range gp.Goals {
+		if !contains(priorities, goal.Priority) {
+			priorities = append(priorities, goal.Priority)
+		}
+	}
+	sort.Ints(priorities)
+	return priorities
+}
+
+func contains(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// penalty возвращает коэффициенты штрафа при переменных задачи с отклонениями
+// для целей, отобранных in: вес при штрафуемых отклонениях, ноль при остальных
+func (gp *GoalProgram) penalty(in func(goal Goal) bool) []*fractional.Fraction {
+	vars := gp.Table.Vars
+	z := make([]*fractional.Fraction, vars+2*len(gp.Goals))
+	for j := range z {
+		z[j] = fractional.ZeroValue
+	}
+	for k, goal := range gp.Goals {
+		if !in(goal) {
+			continue
+		}
+		if goal.Comparison != LessThanOrEqualTo {
+			z[vars+2*k] = goal.Weight
+		}
+		if goal.Comparison != GreaterThanOrEqualTo {
+			z[vars+2*k+1] = goal.Weight
+		}
+	}
+	return z
+}
+
+// deviationTable строит задачу с переменными отклонений: для каждой цели k
+// Σ c·x + dk- - dk+ = target, дополнительно ограничения fixed вида
+// penalty·(x, d) <= значение и целевая функция min objective·(x, d)
+func (gp *GoalProgram) deviationTable(objective []*fractional.Fraction, fixed [][]*fractional.Fraction) *Table {
+	vars := gp.Table.Vars
+	cols := vars + 2*len(gp.Goals)
+	row := func() []*fractional.Fraction {
+		r := make([]*fractional.Fraction, cols+1)
+		for j := range r {
+			r[j] = fractional.ZeroValue
+		}
+		return r
+	}
+	var matrix [][]*fractional.Fraction
+	var comparisons []Comparison
+	var rowNames []string
+	for i := range gp.Table.Rows {
+		r := row()
+		copy(r, gp.Table.Matrix[i][:vars])
+		r[cols] = gp.Table.Matrix[i][gp.Table.Cols-1]
+		matrix = append(matrix, r)
+		comparisons = append(comparisons, gp.Table.comparisons[i])
+		name := gp.Table.RowName(i)
+		if name == "" {
+			name = fmt.Sprintf("c%d", i+1)
+		}
+		rowNames = append(rowNames, name)
+	}
+	for k, goal := range gp.Goals {
+		r := row()
+		copy(r, goal.Coefficients)
+		r[vars+2*k] = fractional.OneValue
+		r[vars+2*k+1] = fractional.RevOneValue
+		r[cols] = goal.Target
+		matrix = append(matrix, r)
+		comparisons = append(comparisons, EqualTo)
+		rowNames = append(rowNames, fmt.Sprintf("goal%d", k+1))
+	}
+	for _, f := range fixed {
+		matrix = append(matrix, f)
+		comparisons = append(comparisons, LessThanOrEqualTo)
+		rowNames = append(rowNames, fmt.Sprintf("fixed%d", len(rowNames)-gp.Table.Rows-len(gp.Goals)+1))
+	}
+
+	t := NewTable(matrix, comparisons, objective, true)
+	for j := range vars {
+		t.VarNames = append(t.VarNames, gp.Table.VarName(j))
+	}
+	for k := range gp.Goals {
+		t.VarNames = append(t.VarNames, fmt.Sprintf("d%d-", k+1), fmt.Sprintf("d%d+", k+1))
+	}
+	t.RowNames = rowNames
+	t.out = gp.Table.out
+	return t
+}
+
+// Weighted решает взвешенную задачу: одна задача линейного программирования
+// с минимизацией суммы взвешенных штрафуемых отклонений всех целей без учёта приоритетов
+func (gp *GoalProgram) Weighted() (*GoalResult, error) {
+	z := gp.penalty(func(Goal) bool { return true })
+	t := gp.deviationTable(z, nil)
+	values, err := t.solve()
+	if err != nil {
+		return nil, err
+	}
+	result := gp.result(values)
+	result.Priorities = []int{0}
+	result.Penalties = []*fractional.Fraction{dot(z, values)}
+	return result, nil
+}
+
+// Lexicographic решает задачу по уровням приоритета: на каждом уровне
+// минимизируется его штраф при условии, что штрафы более высоких уровней
+// не превышают уже найденных оптимальных значений
+func (gp *GoalProgram) Lexicographic() (*GoalResult, error) {
+	var fixed [][]*fractional.Fraction
+	var values []*fractional.Fraction
+	result := &GoalResult{}
+	for _, priority := range gp.Priorities() {
+		z := gp.penalty(func(goal Goal) bool { return goal.Priority == priority })
+		t := gp.deviationTable(z, fixed)
+		fmt.Fprintf(t.output(), "Priority %d:\n", priority)
+		var err error
+		if values, err = t.solve(); err != nil {
+			return nil, err
+		}
+		optimum := dot(z, values)
+		result.Priorities = append(result.Priorities, priority)
+		result.Penalties = append(result.Penalties, optimum)
+		fmt.Fprintf(t.output(), "\npenalty of priority %d = %s\n\n", priority, optimum)
+		fixed = append(fixed, append(z, optimum))
+	}
+	final := gp.result(values)
+	final.Priorities, final.Penalties = result.Priorities, result.Penalties
+	return final, nil
+}
+
+// result разбивает значения переменных задачи с отклонениями на исходные переменные и отклонения
+func (gp *GoalProgram) result(values []*fractional.Fraction) *GoalResult {
+	vars := gp.Table.Vars
+	result := &GoalResult{X: values[:vars]}
+	for k := range gp.Goals {
+		result.Under = append(result.Under, values[vars+2*k])
+		result.Over = append(result.Over, values[vars+2*k+1])
+	}
+	return result
+}
+
+// solve приводит таблицу к канонической форме и базису, решает её двойственным
+// симплекс-методом и возвращает значения исходных переменных
+func (t *Table) solve() ([]*fractional.Fraction, error) {
+	vars := t.Vars
+	fmt.Fprintf(t.output(), "%s\n", t.ToCanonicalForm())
+	basis, err := t.ToBasis()
+	if err != nil {
+		return nil, err
+	}
+	m := New(basis)
+	if err := m.DualMethod(); err != nil {
+		return nil, err
+	}
+	return m.Values()[:vars], nil
+}
+
+func dot(a, b []*fractional.Fraction) *fractional.Fraction {
+	sum := fractional.ZeroValue
+	for j := range min(len(a), len(b)) {
+		sum = sum.Add(*a[j].Multiply(*b[j]))
+	}
+	return sum
+}
+
+func (r *GoalResult) String() string {
+	s := fmt.Sprintf("x = %v\n", r.X)
+	for k := range r.Under {
+		s += fmt.Sprintf("goal %d: d- = %s, d+ = %s\n", k+1, r.Under[k], r.Over[k])
+	}
+	for k, priority := range r.Priorities {
+		if priority == 0 {
+			s += fmt.Sprintf("weighted penalty = %s", r.Penalties[k])
+		} else {
+			s += fmt.Sprintf("priority %d penalty = %s", priority, r.Penalties[k])
+		}
+		if k != len(r.Priorities)-1 {
+			s += "\n"
+		}
+	}
+	return s
+}
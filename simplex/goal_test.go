@@ -0,0 +1,48 @@
+package simplex
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestGoals проверяет штрафы уровней приоритета и их подписи в ответе
+func TestGoals(t *testing.T) {
+	gp, err := ScanGoals(strings.NewReader("1 2 2\n1 1 <= 10\n1 0 >= 8 1\n0 1 >= 5 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gp.Table.SetOutput(io.Discard)
+	result, err := gp.Lexicographic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := result.String(); !strings.Contains(s, "x = [8 2]") || !strings.Contains(s, "priority 1 penalty = 0\npriority 2 penalty = 3") {
+		t.Errorf("lexicographic result:\n%s", s)
+	}
+	if result, err = gp.Weighted(); err != nil {
+		t.Fatal(err)
+	}
+	if s := result.String(); !strings.HasSuffix(s, "weighted penalty = 3") {
+		t.Errorf("weighted result:\n%s", s)
+	}
+}
+
+// TestScanGoalsErrors проверяет, что неверные размеры и приоритеты дают ошибку, а не панику
+func TestScanGoalsErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"1 2\n",
+		"-1 2 2\n1 0 >= 1\n0 1 >= 1\n",
+		"1 2 0\n1 1 <= 10\n",
+		"0 0 1\n>= 1\n",
+		"0 2 1\n1 0 >= 1 0\n",
+		"0 2 1\n1 0 >= 1 -1\n",
+		"0 2 1\n1 0 >= 1 1 2 3\n",
+		"1 2 1\n1 1 <= 10 5\n1 0 >= 1\n",
+	} {
+		if _, err := ScanGoals(strings.NewReader(source)); err == nil {
+			t.Errorf("%q: expected an error", source)
+		}
+	}
+}
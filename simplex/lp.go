@@ -0,0 +1,555 @@
+package simplex
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type lpSection int
+
+const (
+	lpNone lpSection = iota
+	lpMaximize
+	lpMinimize
+	lpConstraints
+	lpBounds
+	lpGeneral
+	lpBinary
+	lpEnd
+)
+
+var lpKeywords = []struct {
+	word    string
+	section lpSection
+}{
+	{"maximize", lpMaximize}, {"maximise", lpMaximize}, {"maximum", lpMaximize}, {"max", lpMaximize},
+	{"minimize", lpMinimize}, {"minimise", lpMinimize}, {"minimum", lpMinimize}, {"min", lpMinimize},
+	{"subject to", lpConstraints}, {"such that", lpConstraints}, {"s.t.", lpConstraints}, {"st.", lpConstraints}, {"st", lpConstraints},
+	{"bounds", lpBounds}, {"bound", lpBounds},
+	{"generals", lpGeneral}, {"general", lpGeneral}, {"gen", lpGeneral},
+	{"binaries", lpBinary}, {"binary", lpBinary}, {"bin", lpBinary},
+	{"end", lpEnd},
+}
+
+var (
+	errInfinite = errors.New("infinite value")
+
+	lpConstraintPattern = regexp.MustCompile(
+		`(?s)\s*(?:([^\s:<>=+\-]+)\s*:)?(.*?)(<=|>=|=<|=>|<|>|=)\s*([+-]?\s*(?:[0-9][0-9./]*|\.[0-9]+)(?:[eE][+-]?[0-9]+)?)`)
+	lpOperatorPattern = regexp.MustCompile(`(<=|>=|=<|=>|<|>|=)`)
+)
+
+// ReadLP считывает задачу в формате CPLEX LP. Границы переменных из секций
+// Bounds и Binary добавляются в таблицу отдельными ограничениями, так как
+// симплекс-метод работает только с неотрицательными переменными
+func ReadLP(r io.Reader) (*Table, error) {
+	sections := make(map[lpSection]*strings.Builder)
+	var order []lpSection
+	current := lpNone
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), `\`)
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if section, rest, ok := lpSectionHeader(line); ok {
+			current = section
+			if _, exists := sections[section]; !exists {
+				sections[section] = &strings.Builder{}
+				order = append(order, section)
+			}
+			line = rest
+		}
+		if current == lpEnd {
+			break
+		}
+		if current == lpNone {
+			return nil, fmt.Errorf("unexpected line before objective: %q", line)
+		}
+		sections[current].WriteString(line)
+		sections[current].WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	p := &algebraicParser{index: make(map[string]int)}
+	var objective *linearExpression
+	for _, section := range order {
+		if section != lpMaximize && section != lpMinimize {
+			continue
+		}
+		if objective != nil {
+			return nil, fmt.Errorf("objective function is defined twice")
+		}
+		body := sections[section].String()
+		if label, rest, ok := strings.Cut(body, ":"); ok && isIdentifier(strings.TrimSpace(label)) {
+			body = rest
+		}
+		var err error
+		if objective, err = p.parseOptionalExpression(body); err != nil {
+			return nil, err
+		}
+		p.isMinimization = section == lpMinimize
+	}
+	if objective == nil {
+		return nil, fmt.Errorf("objective section (Maximize or Minimize) is missing")
+	}
+
+	if text, ok := sections[lpConstraints]; ok {
+		if err := p.parseLPConstraints(text.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds := make(map[int]*lpBound)
+	if text, ok := sections[lpBounds]; ok {
+		for _, line := range strings.Split(text.String(), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if err := p.parseLPBound(line, bounds); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	integer := make(map[int]bool)
+	for _, section := range []lpSection{lpGeneral, lpBinary} {
+		text, ok := sections[section]
+		if !ok {
+			continue
+		}
+		for _, name := range strings.Fields(text.String()) {
+			j := p.variable(name)
+			integer[j] = true
+			if section == lpBinary {
+				bounds[j] = &lpBound{lower: fractional.ZeroValue, upper: fractional.OneValue}
+			}
+		}
+	}
+
+	p.appendBounds(objective, bounds)
+	return p.table(objective, integer), nil
+}
+
+func lpSectionHeader(line string) (lpSection, string, bool) {
+	lower := strings.ToLower(line)
+	for _, keyword := range lpKeywords {
+		if !strings.HasPrefix(lower, keyword.word) {
+			continue
+		}
+		rest := line[len(keyword.word):]
+		if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+			return keyword.section, strings.TrimSpace(rest), true
+		}
+	}
+	return lpNone, "", false
+}
+
+// parseOptionalExpression разбирает выражение, допуская пустую запись
+func (p *algebraicParser) parseOptionalExpression(s string) (*linearExpression, error) {
+	if strings.TrimSpace(s) == "" {
+		return &linearExpression{
+			coefficients: make(map[int]*fractional.Fraction),
+			constant:     fractional.ZeroValue,
+		}, nil
+	}
+	return p.parseExpression(s)
+}
+
+func (p *algebraicParser) parseLPConstraints(text string) error {
+	position := 0
+	for _, match := range lpConstraintPattern.FindAllStringSubmatchIndex(text, -1) {
+		if gap := strings.TrimSpace(text[position:match[0]]); gap != "" {
+			return fmt.Errorf("unexpected text in constraints: %q", gap)
+		}
+		position = match[1]
+
+		var name string
+		if match[2] != -1 {
+			name = text[match[2]:match[3]]
+		}
+		sign := text[match[6]:match[7]]
+		switch sign {
+		case "<", "=<":
+			sign = "<="
+		case ">", "=>":
+			sign = ">="
+		}
+		comparison, err := parseComparison(sign)
+		if err != nil {
+			return err
+		}
+		lhs, err := p.parseOptionalExpression(text[match[4]:match[5]])
+		if err != nil {
+			return err
+		}
+		rhs, err := parseNumber(strings.ReplaceAll(text[match[8]:match[9]], " ", ""))
+		if err != nil {
+			return fmt.Errorf("constraint %q: %w", name, err)
+		}
+		lhs.constant = rhs.Subtract(*lhs.constant)
+		lhs.comparison = comparison
+		lhs.name = name
+		p.constraints = append(p.constraints, lhs)
+	}
+	if rest := strings.TrimSpace(text[position:]); rest != "" {
+		return fmt.Errorf("unexpected text in constraints: %q", rest)
+	}
+	return nil
+}
+
+// lpBound хранит границы переменной, nil означает бесконечность
+type lpBound struct {
+	lower, upper *fractional.Fraction
+}
+
+func (p *algebraicParser) parseLPBound(line string, bounds map[int]*lpBound) error {
+	var fields []string
+	for _, field := range strings.Fields(lpOperatorPattern.ReplaceAllString(line, " $1 ")) {
+		if n := len(fields); n > 0 && (fields[n-1] == "+" || fields[n-1] == "-") {
+			fields[n-1] += field
+			continue
+		}
+		fields = append(fields, field)
+	}
+
+	bound := func(name string) *lpBound {
+		j := p.variable(name)
+		if _, ok := bounds[j]; !ok {
+			bounds[j] = &lpBound{lower: fractional.ZeroValue}
+		}
+		return bounds[j]
+	}
+	set := func(b *lpBound, sign string, value string) error {
+		v, err := parseNumber(value)
+		infinite := errors.Is(err, errInfinite)
+		if err != nil && !infinite {
+			return fmt.Errorf("bound %q: %w", strings.TrimSpace(line), err)
+		}
+		switch {
+		case sign == "=":
+			b.lower, b.upper = v, v
+		case sign == "<=" && infinite:
+			b.upper = nil
+		case sign == "<=":
+			b.upper = v
+		case sign == ">=" && infinite:
+			b.lower = nil
+		default:
+			b.lower = v
+		}
+		return nil
+	}
+	flip := map[string]string{"<=": ">=", "<": ">=", "=<": ">=", ">=": "<=", ">": "<=", "=>": "<=", "=": "="}
+	normalize := func(sign string) string { return flip[flip[sign]] }
+
+	switch {
+	case len(fields) == 2 && strings.EqualFold(fields[1], "free"):
+		b := bound(fields[0])
+		b.lower, b.upper = nil, nil
+		return nil
+	case len(fields) == 3 && isIdentifier(fields[0]) && !isInfinity(fields[0]):
+		return set(bound(fields[0]), normalize(fields[1]), fields[2])
+	case len(fields) == 3 && isIdentifier(fields[2]):
+		return set(bound(fields[2]), flip[fields[1]], fields[0])
+	case len(fields) == 5 && isIdentifier(fields[2]):
+		b := bound(fields[2])
+		if err := set(b, flip[fields[1]], fields[0]); err != nil {
+			return err
+		}
+		return set(b, normalize(fields[3]), fields[4])
+	}
+	return fmt.Errorf("invalid bound: %q", strings.TrimSpace(line))
+}
+
+// appendBounds добавляет границы переменных в виде ограничений. Переменная
+// без нижней границы заменяется разностью x - x_neg двух неотрицательных,
+// переменная с отрицательной нижней границей l - сдвинутой x' = x - l;
+// замены сохраняются в p.substitutions
+func (p *algebraicParser) appendBounds(objective *linearExpression, bounds map[int]*lpBound) {
+	n := len(p.names)
+	for j := range n {
+		b, ok := bounds[j]
+		if !ok {
+			continue
+		}
+		coefficients := map[int]*fractional.Fraction{j: fractional.OneValue}
+		lower := b.lower
+		switch {
+		case lower == nil:
+			negative := p.variable(p.unusedName(p.names[j] + "_neg"))
+			for _, e := range p.constraints {
+				if c, ok := e.coefficients[j]; ok {
+					e.coefficients[negative] = c.Reverse()
+				}
+			}
+			if c, ok := objective.coefficients[j]; ok {
+				objective.coefficients[negative] = c.Reverse()
+			}
+			coefficients[negative] = fractional.RevOneValue
+			p.substitute(n, j, negative, fractional.ZeroValue)
+		case lower.LessThan(*fractional.ZeroValue):
+			for _, c := range p.constraints {
+				c.constant = c.constant.Subtract(*c.coefficient(j).Multiply(*lower))
+			}
+			objective.constant = objective.constant.Add(*objective.coefficient(j).Multiply(*lower))
+			p.substitute(n, j, -1, lower)
+		}
+
+		row := func(comparison Comparison, value *fractional.Fraction) {
+			if lower != nil && lower.LessThan(*fractional.ZeroValue) {
+				value = value.Subtract(*lower)
+			}
+			p.constraints = append(p.constraints, &linearExpression{
+				coefficients: coefficients,
+				constant:     value,
+				comparison:   comparison,
+			})
+		}
+		switch {
+		case lower != nil && b.upper != nil && b.upper.Equal(*lower):
+			row(EqualTo, b.upper)
+			continue
+		case lower != nil && lower.GreaterThan(*fractional.ZeroValue):
+			row(GreaterThanOrEqualTo, lower)
+		}
+		if b.upper != nil {
+			row(LessThanOrEqualTo, b.upper)
+		}
+	}
+}
+
+// substitute запоминает замену x_j = x_j - x_negative + shift для одной из
+// n исходных переменных, negative = -1, если переменная только сдвинута
+func (p *algebraicParser) substitute(n, j, negative int, shift *fractional.Fraction) {
+	if p.substitutions == nil {
+		p.substitutions = make([]Substitution, n)
+		for k := range n {
+			p.substitutions[k] = Substitution{Name: p.names[k], Positive: k, Negative: -1, Shift: fractional.ZeroValue}
+		}
+	}
+	p.substitutions[j].Negative, p.substitutions[j].Shift = negative, shift
+}
+
+// unusedName возвращает name или name с номером, если такая переменная уже есть
+func (p *algebraicParser) unusedName(name string) string {
+	candidate := name
+	for k := 2; ; k++ {
+		if _, exists := p.index[candidate]; !exists {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, k)
+	}
+}
+
+// table собирает таблицу из разобранной целевой функции и ограничений
+func (p *algebraicParser) table(objective *linearExpression, integer map[int]bool) *Table {
+	vars := len(p.names)
+	matrix := make([][]*fractional.Fraction, len(p.constraints))
+	comparisons := make([]Comparison, len(p.constraints))
+	rowNames := make([]string, len(p.constraints))
+	for i, c := range p.constraints {
+		matrix[i] = make([]*fractional.Fraction, vars+1)
+		for j := range vars {
+			matrix[i][j] = c.coefficient(j)
+		}
+		matrix[i][vars] = c.constant
+		comparisons[i] = c.comparison
+		rowNames[i] = c.name
+	}
+	z := make([]*fractional.Fraction, vars)
+	for j := range vars {
+		z[j] = objective.coefficient(j)
+	}
+
+	t := NewTable(matrix, comparisons, z, p.isMinimization)
+	t.setObjectiveConstant(objective.constant)
+	t.VarNames = p.names
+	t.RowNames = rowNames
+	t.Substitutions = p.substitutions
+	if len(integer) > 0 {
+		t.Integer = make([]bool, vars)
+		for j := range integer {
+			t.Integer[j] = true
+		}
+		for _, s := range p.substitutions {
+			if s.Negative >= 0 && t.Integer[s.Positive] {
+				t.Integer[s.Negative] = true
+			}
+		}
+	}
+	return t
+}
+
+// WriteLP записывает задачу в формате CPLEX LP. Дроби, не представимые
+// конечной десятичной записью, выводятся в виде n/d
+func (t *Table) WriteLP(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	names := make([]string, t.Cols-1)
+	for j := range names {
+		names[j] = t.VarName(j)
+	}
+
+	fmt.Fprintln(bw, `\ Problem written by kw-algos`)
+	if t.IsMinimizationProblem {
+		fmt.Fprintln(bw, "Minimize")
+	} else {
+		fmt.Fprintln(bw, "Maximize")
+	}
+	// В целевой функции перечисляются все переменные, чтобы при чтении
+	// сохранились их порядок и столбцы без ненулевых коэффициентов
+	objective := formatLPExpression(t.Objective(), names, true)
+	if constant := t.ObjectiveConstant(); constant.NotEqual(*fractional.ZeroValue) {
+		objective += formatLPTerm(constant, "", false)
+	}
+	fmt.Fprintf(bw, " obj: %s\n", objective)
+
+	fmt.Fprintln(bw, "Subject To")
+	comparisons := t.constraintComparisons()
+	for i := range t.Rows {
+		fmt.Fprint(bw, " ")
+		if name := t.RowName(i); name != "" {
+			fmt.Fprintf(bw, "%s: ", name)
+		}
+		fmt.Fprintf(bw, "%s %s %s\n",
+			formatLPExpression(t.Matrix[i][:t.Cols-1], names, false),
+			&comparisons[i],
+			formatNumber(t.Matrix[i][t.Cols-1]))
+	}
+
+	var integer []string
+	for j := range names {
+		if t.isInteger(j) {
+			integer = append(integer, names[j])
+		}
+	}
+	if len(integer) > 0 {
+		fmt.Fprintln(bw, "General")
+		fmt.Fprintf(bw, " %s\n", strings.Join(integer, " "))
+	}
+	fmt.Fprintln(bw, "End")
+	return bw.Flush()
+}
+
+// constraintComparisons возвращает знаки ограничений, считая отсутствующие равенствами
+func (t *Table) constraintComparisons() []Comparison {
+	comparisons := make([]Comparison, t.Rows)
+	copy(comparisons, t.comparisons)
+	return comparisons
+}
+
+func formatLPExpression(coefficients []*fractional.Fraction, names []string, withZeros bool) string {
+	var s strings.Builder
+	terms := 0
+	for j, c := range coefficients {
+		if c.Equal(*fractional.ZeroValue) && !withZeros {
+			continue
+		}
+		if terms > 0 && terms%8 == 0 {
+			s.WriteString("\n  ")
+		}
+		s.WriteString(formatLPTerm(c, names[j], terms == 0))
+		terms++
+	}
+	if terms == 0 {
+		return "0 " + names[0]
+	}
+	return s.String()
+}
+
+func formatLPTerm(c *fractional.Fraction, name string, first bool) string {
+	var s string
+	switch {
+	case c.LessThan(*fractional.ZeroValue) && first:
+		s = "-"
+	case c.LessThan(*fractional.ZeroValue):
+		s = " - "
+	case !first:
+		s = " + "
+	}
+	if name == "" {
+		return s + formatNumber(c.Abs())
+	}
+	if c.Abs().Equal(*fractional.OneValue) {
+		return s + name
+	}
+	return s + formatNumber(c.Abs()) + " " + name
+}
+
+// formatNumber выводит дробь конечной десятичной записью, если это возможно
+func formatNumber(f *fractional.Fraction) string {
+	d := f.Denominator()
+	var twos, fives int
+	for ; d%2 == 0; d /= 2 {
+		twos++
+	}
+	for ; d%5 == 0; d /= 5 {
+		fives++
+	}
+	if d != 1 {
+		return f.String()
+	}
+	digits := max(twos, fives)
+	if digits == 0 || digits > 18 {
+		return f.String()
+	}
+	scale := int64(1)
+	for range digits {
+		scale *= 10
+	}
+	multiplier := scale / f.Denominator()
+	if abs64(f.Numerator()) > math.MaxInt64/multiplier {
+		return f.String()
+	}
+	n := f.Numerator() * multiplier
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, n/scale, digits, n%scale)
+}
+
+// parseNumber разбирает число, в том числе в экспоненциальной записи;
+// значения inf, infinity и не меньшие 1e30 по модулю считаются бесконечными
+func parseNumber(s string) (*fractional.Fraction, error) {
+	if isInfinity(s) {
+		return nil, errInfinite
+	}
+	if !strings.ContainsAny(s, "eE") {
+		return fractional.Parse(s)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", fractional.ErrInvalidSyntax, s)
+	}
+	if math.Abs(v) >= 1e30 {
+		return nil, errInfinite
+	}
+	return fractional.Parse(strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+func isInfinity(s string) bool {
+	switch strings.ToLower(strings.TrimLeft(s, "+-")) {
+	case "inf", "infinity":
+		return true
+	}
+	return false
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
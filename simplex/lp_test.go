@@ -0,0 +1,78 @@
+package simplex
+
+import (
+	"io"
+	"kw-algos/fractional"
+	"strings"
+	"testing"
+)
+
+// TestReadLPExponent проверяет, что коэффициенты в экспоненциальной записи
+// читаются как числа, а не как переменная e
+func TestReadLPExponent(t *testing.T) {
+	table, err := ReadLP(strings.NewReader("Maximize\n obj: 1.5e-3 x + 2E+1 y + 3e + z\nSubject To\n c1: x + y + z <= 1e2\nEnd\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(table.VarNames, " ") != "x y e z" {
+		t.Fatalf("variables %v", table.VarNames)
+	}
+	for j, expected := range []string{"3/2000", "20", "3", "1"} {
+		if table.Z[j].String() != expected {
+			t.Errorf("coefficient of %s is %s, expected %s", table.VarName(j), table.Z[j], expected)
+		}
+	}
+}
+
+// TestReadLPBounds проверяет, что свободные переменные и отрицательные нижние
+// границы заменяются неотрицательными переменными, а ответ переводится обратно
+func TestReadLPBounds(t *testing.T) {
+	for _, c := range []struct {
+		source, expected, value string
+	}{
+		{"Minimize\n obj: x + 2 y\nSubject To\n c1: x + y >= -3\n c2: x - y <= 1\nBounds\n x free\n -4 <= y <= 5\nEnd\n", "-1 -2", "-5"},
+		{"Maximize\n obj: x + y + 3\nSubject To\n c1: x + 2 y <= 4\n c2: x - y >= -10\nBounds\n x <= 2\n y >= -inf\n -5 <= z <= -5\nEnd\n", "2 1 -5", "6"},
+		{"Maximize\n obj: -x\nSubject To\n c1: x >= -7\nBounds\n -inf <= x <= 3\nEnd\n", "-7", "7"},
+	} {
+		table, err := ReadLP(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		table.SetOutput(io.Discard)
+		original := table.Clone()
+		table.ToCanonicalForm()
+		basis, err := table.ToBasis()
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		m := New(basis)
+		m.MaxIterations = 100
+		m.Restore = func(x []*fractional.Fraction) ([]*fractional.Fraction, []string) {
+			return original.Unsubstitute(x), nil
+		}
+		if err := m.DualMethod(); err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		if m.Alternative() {
+			t.Errorf("%q: the optimum is unique in the original variables, got %s", c.source, m.Face)
+		}
+		certificate, err := m.Verify()
+		if err == nil {
+			err = original.Verify(certificate)
+		}
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		var x []string
+		for _, v := range original.Unsubstitute(certificate.X) {
+			x = append(x, v.String())
+		}
+		value := original.ObjectiveConstant()
+		for j, v := range certificate.X {
+			value = value.Add(*original.Z[j].Multiply(*v))
+		}
+		if strings.Join(x, " ") != c.expected || value.String() != c.value {
+			t.Errorf("%q: x = %v, Z = %s, expected %s and %s", c.source, x, value, c.expected, c.value)
+		}
+	}
+}
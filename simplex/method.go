@@ -1,10 +1,30 @@
 package simplex
 
 import (
+	"errors"
 	"fmt"
 	"kw-algos/fractional"
 )
 
+// ErrNoSolutions - задача несовместна либо целевая функция неограничена
+var ErrNoSolutions = errors.New("no solutions\n")
+
+var ErrIterationLimit = errors.New("iteration limit reached")
+
+// PivotRule - правило выбора разрешающего элемента
+type PivotRule int
+
+const (
+	// Dantzig выбирает наибольшую по модулю отрицательную оценку или свободный член
+	Dantzig PivotRule = iota
+	// Bland выбирает переменную с наименьшим номером и исключает зацикливание
+	Bland
+)
+
+func (r PivotRule) String() string {
+	return [...]string{"dantzig", "bland"}[r]
+}
+
 type Methods interface {
 	DualMethod()
 }
@@ -13,6 +33,21 @@ type Method struct {
 	Table        *Table
 	CO           []*fractional.Fraction
 	isDualMethod bool
+	// Face - множество оптимальных решений, заполняется после решения задачи
+	Face *OptimalFace
+	Rule PivotRule
+	// MaxIterations ограничивает число итераций, 0 - без ограничения
+	MaxIterations int
+	// Farkas - множители исходных ограничений, доказывающие несовместность,
+	// заполняется, когда в строке с отрицательным свободным членом нет отрицательных элементов
+	Farkas []*fractional.Fraction
+	// Ray - направление неограниченного роста целевой функции в исходных
+	// переменных из текущего плана, заполняется, когда в столбце с
+	// отрицательной оценкой нет положительных элементов
+	Ray []*fractional.Fraction
+	// Restore переводит значения переменных таблицы в значения и имена
+	// переменных исходной задачи для печати ответа; nil - печатается таблица
+	Restore func(x []*fractional.Fraction) ([]*fractional.Fraction, []string)
 }
 
 func New(table *Table) *Method {
@@ -25,17 +60,23 @@ func New(table *Table) *Method {
 func (m *Method) String() string {
 	var s string
 	var offset = 8
+	// Ширина столбца имён базисных переменных по самому длинному имени
+	label := 2
+	for j := range m.Table.Cols - 1 {
+		label = max(label, len(m.Table.VarName(j)))
+	}
+	label += 2
 
-	s += fmt.Sprintf(" B.V%*s%*d%*s", 2, "|", offset, 1, 2, "|")
+	s += fmt.Sprintf(" %-*s|%*d%*s", label, "B.V", offset, 1, 2, "|")
 	for i := range m.Table.Cols - 1 {
-		s += fmt.Sprintf("%*sx%d%*s", offset/2, "", i+1, offset/2-2, "")
+		s += fmt.Sprintf("%*s%-*s", offset/2, "", offset/2, m.Table.VarName(i))
 	}
 	if !m.isDualMethod {
 		s += " |	CO"
 	}
 	s += "\n"
 	for i := 0; i < m.Table.Rows; i++ {
-		s += fmt.Sprintf(" x%d%*s", m.Table.BasisVars[i]+1, 3, "|")
+		s += fmt.Sprintf(" %-*s|", label, m.Table.VarName(m.Table.BasisVars[i]))
 		s += fmt.Sprintf("%*s", offset, m.Table.Matrix[i][m.Table.Cols-1])
 		s += fmt.Sprintf("%*s", 2, "|")
 		for j := 0; j < m.Table.Cols-1; j++ {
@@ -55,7 +96,7 @@ func (m *Method) String() string {
 		}
 		s += "\n"
 	}
-	s += fmt.Sprintf("  Z%*s", 3, "|")
+	s += fmt.Sprintf("  %-*s|", label-1, "Z")
 	s += fmt.Sprintf("%*s", offset, m.Table.ZFree)
 	s += fmt.Sprintf("%*s", 2, "|")
 	for j, z := range m.Table.Z {
@@ -66,7 +107,7 @@ func (m *Method) String() string {
 		s += fmt.Sprintf("%*s", offset, z)
 	}
 	if m.isDualMethod {
-		s += fmt.Sprintf("\n CO%*s%*s%*s", 3, "|", offset, "", 2, "|")
+		s += fmt.Sprintf("\n %-*s|%*s%*s", label, "CO", offset, "", 2, "|")
 
 		for i, co := range m.CO {
 			offset := offset
@@ -91,10 +132,7 @@ func (m *Method) String() string {
 func (m *Method) DualMethod() error {
 	convertZString(m.Table)
 
-	InfinityCycles := -1
-	var infinityCopyTable *Table
-
-	for {
+	for iteration := 0; ; iteration++ {
 		var resolveRow, resolveColumn int
 
 		isOptimal := true
@@ -123,8 +161,7 @@ func (m *Method) DualMethod() error {
 			}
 		}
 
-		//	В Z-строке ищем нет ли нулей (признак того что решение не единственое)
-		//	параллельно проверяем есть ли в строке отрицателные элементы
+		//	Проверяем есть ли в Z-строке отрицателные элементы
 		// 	(если есть отриц. элемент и при этом 1ый признак оптимальности присутствует, нужно применить обычный симплекс метод)
 		minNegativeZValueIndex := 0
 		for i, z := range m.Table.Z {
@@ -133,30 +170,28 @@ func (m *Method) DualMethod() error {
 				if m.Table.Z[minNegativeZValueIndex].GreaterThan(*z) {
 					minNegativeZValueIndex = i
 				}
-			} else if _, ok := m.Table.IsContainedInBasis(i); z.Equal(*fractional.ZeroValue) && !ok {
-				resolveColumn = i
-				if InfinityCycles == 1 {
-					linearCombinations(m.Table, infinityCopyTable)
-					m.printAnswer()
-					return nil
-				}
-				InfinityCycles++
-				if InfinityCycles == 0 {
-					fmt.Println("solution is optimal, but not the only one")
-				}
-				infinityCopyTable = &Table{
-					Z:         m.Table.CopyZ(),
-					ZFree:     m.Table.CopyZFree(),
-					Matrix:    m.Table.CopyMatrix(),
-					BasisVars: m.Table.CopyBasisVars(),
-				}
-				break
 			}
 		}
 
-		//	Если есть 1ый признак оптиальности, Z-строка положительная и нет признака того что реш. не единственно - Получено оптимальное решение!
-		if isOptimal && !isZStringIsNegative && InfinityCycles == -1 {
-			fmt.Println(m)
+		if m.Rule == Bland {
+			resolveRow, minNegativeZValueIndex = m.blandChoice(resolveRow, minNegativeZValueIndex)
+		}
+
+		//	Если есть 1ый признак оптиальности и Z-строка положительная - Получено оптимальное решение!
+		//	Нулевые оценки небазисных переменных говорят о том, что решение не единственное
+		if isOptimal && !isZStringIsNegative {
+			fmt.Fprintln(m.Table.output(), m)
+			face, err := m.optimalFace()
+			if err != nil {
+				return err
+			}
+			m.Face = face
+			if m.Alternative() {
+				fmt.Fprintln(m.Table.output(), "solution is optimal, but not the only one")
+				fmt.Fprintln(m.Table.output(), face)
+			} else if face.Truncated {
+				fmt.Fprintf(m.Table.output(), "%s, uniqueness is not established\n", ErrFaceTruncated)
+			}
 			m.printAnswer()
 			return nil
 		}
@@ -174,12 +209,10 @@ func (m *Method) DualMethod() error {
 					m.CO[j] = divide.Abs()
 				}
 			}
-			fmt.Println(m)
+			fmt.Fprintln(m.Table.output(), m)
 		} else {
 			// Вычисление обычных CO
-			if InfinityCycles == -1 {
-				resolveColumn = minNegativeZValueIndex
-			}
+			resolveColumn = minNegativeZValueIndex
 			m.CO = make([]*fractional.Fraction, m.Table.Rows)
 			var err error
 			for i := range m.Table.Rows {
@@ -192,49 +225,95 @@ func (m *Method) DualMethod() error {
 				}
 			}
 			m.isDualMethod = false
-			fmt.Println(m)
+			fmt.Fprintln(m.Table.output(), m)
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(m.Table.output(), "\n")
 
 		if isOptimal && !isResolveColumnIsPositive {
-			return fmt.Errorf("no solutions\n")
+			m.Ray = m.direction(resolveColumn)
+			return ErrNoSolutions
 		}
-		if !isResolveRowIsNegative && InfinityCycles == -1 {
-			if !isOptimal {
-				return fmt.Errorf("no solutions\n")
+		if !isOptimal && !isResolveRowIsNegative {
+			farkas, err := m.farkas(resolveRow)
+			if err != nil {
+				return err
 			}
+			m.Farkas = farkas
+			return ErrNoSolutions
 		}
 
-		if InfinityCycles == -1 && !isOptimal {
+		if m.MaxIterations > 0 && iteration >= m.MaxIterations {
+			return ErrIterationLimit
+		}
+		if !isOptimal {
 			resolveColumn = m.findMinimumValueInCO()
 		} else {
 			resolveRow = m.findMinimumValueInCO()
+			if m.Rule == Bland {
+				resolveRow = m.blandRatioRow(resolveRow)
+			}
 		}
-
-		newTable := &Table{
-			Z:      m.Table.CopyZ(),
-			ZFree:  m.Table.CopyZFree(),
-			Matrix: m.Table.CopyMatrix(),
+		if err := m.pivot(resolveRow, resolveColumn); err != nil {
+			return err
 		}
+	}
+}
 
-		resolver := m.Table.Matrix[resolveRow][resolveColumn]
-		for j := range m.Table.Cols {
-			var err error
-			newTable.Matrix[resolveRow][j], err = m.Table.Matrix[resolveRow][j].Divide(*resolver)
-			if err != nil {
-				return err
-			}
-		}
+// pivot делает переменную resolveColumn базисной в строке resolveRow
+func (m *Method) pivot(resolveRow, resolveColumn int) error {
+	newTable := &Table{
+		Z:      m.Table.CopyZ(),
+		ZFree:  m.Table.CopyZFree(),
+		Matrix: m.Table.CopyMatrix(),
+	}
 
-		if err := m.methodRectangle(newTable, resolveRow, resolveColumn); err != nil {
+	resolver := m.Table.Matrix[resolveRow][resolveColumn]
+	for j := range m.Table.Cols {
+		var err error
+		newTable.Matrix[resolveRow][j], err = m.Table.Matrix[resolveRow][j].Divide(*resolver)
+		if err != nil {
 			return err
 		}
+	}
 
-		m.Table.Matrix = newTable.Matrix
-		m.Table.Z = newTable.Z
-		m.Table.ZFree = newTable.ZFree
-		m.Table.BasisVars[resolveRow] = resolveColumn
+	if err := m.methodRectangle(newTable, resolveRow, resolveColumn); err != nil {
+		return err
+	}
+
+	m.Table.Matrix = newTable.Matrix
+	m.Table.Z = newTable.Z
+	m.Table.ZFree = newTable.ZFree
+	m.Table.BasisVars[resolveRow] = resolveColumn
+	return nil
+}
+
+// blandChoice выбирает по правилу Бленда строку с отрицательным свободным
+// членом и наименьшим номером базисной переменной и столбец с отрицательной
+// оценкой и наименьшим номером
+func (m *Method) blandChoice(row, column int) (int, int) {
+	for i := range m.Table.Rows {
+		if m.Table.Matrix[i][m.Table.Cols-1].LessThan(*fractional.ZeroValue) &&
+			(!m.Table.Matrix[row][m.Table.Cols-1].LessThan(*fractional.ZeroValue) || m.Table.BasisVars[i] < m.Table.BasisVars[row]) {
+			row = i
+		}
+	}
+	for j, z := range m.Table.Z {
+		if z.LessThan(*fractional.ZeroValue) {
+			return row, j
+		}
 	}
+	return row, column
+}
+
+// blandRatioRow среди строк с тем же минимальным отношением выбирает строку
+// с наименьшим номером базисной переменной
+func (m *Method) blandRatioRow(row int) int {
+	for i, co := range m.CO {
+		if co != nil && co.Equal(*m.CO[row]) && m.Table.BasisVars[i] < m.Table.BasisVars[row] {
+			row = i
+		}
+	}
+	return row
 }
 
 func (m *Method) findMinimumValueInCO() int {
@@ -259,32 +338,6 @@ func (m *Method) findMinimumValueInCO() int {
 	return minIndex
 }
 
-func linearCombinations(table1 *Table, table2 *Table) {
-	findIndex := func(varIndex int, table *Table) *fractional.Fraction {
-		for i, basisVar := range table.BasisVars {
-			if varIndex == basisVar {
-				return table.Matrix[i][table1.Cols-1]
-			}
-		}
-		return fractional.ZeroValue
-	}
-	fmt.Printf("x^(*) = (")
-	for i := range table1.Vars {
-		x2 := findIndex(i, table1)
-		x1 := findIndex(i, table2)
-		sub := x2.Subtract(*x1)
-		if sub.LessThan(*fractional.ZeroValue) {
-			fmt.Printf("%s%sλ", x1, sub)
-		} else {
-			fmt.Printf("%s+%sλ", x1, sub)
-		}
-		if i != table1.Vars-1 {
-			fmt.Print("; ")
-		}
-	}
-	fmt.Print(")")
-}
-
 func convertZString(t *Table) {
 	for z := range t.Z {
 		if _, ok := t.IsContainedInBasis(z); ok && t.Z[z].NotEqual(*fractional.ZeroValue) {
@@ -375,23 +428,43 @@ func (m *Method) methodRectangle(newTable *Table, resolveRow, resolveColumn int)
 	return nil
 }
 
+// Values возвращает значения исходных переменных в текущем базисе
+func (m *Method) Values() []*fractional.Fraction {
+	values := make([]*fractional.Fraction, m.Table.Vars)
+	for i := range m.Table.Vars {
+		values[i] = fractional.ZeroValue
+		if index, ok := m.Table.IsContainedInBasis(i); ok {
+			values[i] = m.Table.Matrix[index][m.Table.Cols-1]
+		}
+	}
+	return values
+}
+
 func (m *Method) printAnswer() {
-	fmt.Printf("\n")
+	w := m.Table.output()
+	fmt.Fprintf(w, "\n")
 	if m.Table.IsMinimizationProblem {
-		fmt.Print("Zmin(")
+		fmt.Fprint(w, "Zmin(")
 		m.Table.ZFree = m.Table.ZFree.Reverse()
 	} else {
-		fmt.Print("Zmax(")
+		fmt.Fprint(w, "Zmax(")
 	}
-	for i := range m.Table.Vars {
-		if index, ok := m.Table.IsContainedInBasis(i); ok {
-			fmt.Printf("%s", m.Table.Matrix[index][m.Table.Cols-1])
-		} else {
-			fmt.Print("0")
+	x, names := m.Values(), []string(nil)
+	if m.Restore != nil {
+		x, names = m.Restore(x)
+	} else if len(m.Table.VarNames) > 0 {
+		for i := range x {
+			names = append(names, m.Table.VarName(i))
+		}
+	}
+	for i, v := range x {
+		if len(names) > 0 {
+			fmt.Fprintf(w, "%s=", names[i])
 		}
-		if i != m.Table.Vars-1 {
-			fmt.Print(";")
+		fmt.Fprintf(w, "%s", v)
+		if i != len(x)-1 {
+			fmt.Fprint(w, ";")
 		}
 	}
-	fmt.Printf(") = %s\n", m.Table.ZFree)
+	fmt.Fprintf(w, ") = %s\n", m.Table.ZFree)
 }
@@ -0,0 +1,36 @@
+package simplex
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestMethodStringLabels проверяет, что длинные имена базисных переменных
+// не сдвигают столбец свободных членов
+func TestMethodStringLabels(t *testing.T) {
+	table, err := ScanAlgebraic(strings.NewReader("max: 3apples + 2x10;\nc1: apples + x10 <= 4;\nc2: apples + 3x10 >= 6;\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.SetOutput(io.Discard)
+	table.ToCanonicalForm()
+	basis, err := table.ToBasis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(basis)
+	if err := m.DualMethod(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Table.VarName(m.Table.BasisVars[0]) != "apples" {
+		t.Fatalf("expected apples in the optimal basis:\n%s", m)
+	}
+	lines := strings.Split(m.String(), "\n")
+	column := strings.Index(lines[0], "|")
+	for _, line := range lines[1:] {
+		if k := strings.Index(line, "|"); k != column {
+			t.Errorf("separator at %d instead of %d:\n%s", k, column, m)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package simplex
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"strings"
+)
+
+// ReadMPS считывает задачу в формате MPS (фиксированном или свободном).
+// Поля разделяются пробелами, поэтому имена с пробелами не поддерживаются.
+// Диапазоны из секции RANGES и границы из BOUNDS добавляются в таблицу
+// отдельными ограничениями, как и в ReadLP. Без секции OBJSENSE задача
+// считается задачей на минимум, как принято в MPS
+func ReadMPS(r io.Reader) (*Table, error) {
+	p := &algebraicParser{index: make(map[string]int), isMinimization: true}
+	objective := &linearExpression{
+		coefficients: make(map[int]*fractional.Fraction),
+		constant:     fractional.ZeroValue,
+	}
+	var objectiveName string
+	rows := make(map[string]*linearExpression)
+	// freeRows - свободные строки кроме целевой функции, их значения пропускаются
+	freeRows := make(map[string]bool)
+	bounds := make(map[int]*lpBound)
+	integer := make(map[int]bool)
+	var ranged []*linearExpression
+	ranges := make(map[*linearExpression]*fractional.Fraction)
+
+	row := func(name string) (*linearExpression, error) {
+		if name == objectiveName {
+			return objective, nil
+		}
+		if e, ok := rows[name]; ok {
+			return e, nil
+		}
+		if freeRows[name] {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unknown row %q", name)
+	}
+	// pairs обрабатывает пары "строка значение", начиная с поля start
+	pairs := func(fields []string, start int, apply func(*linearExpression, *fractional.Fraction) error) error {
+		if (len(fields)-start)%2 != 0 {
+			return fmt.Errorf("invalid line: %q", strings.Join(fields, " "))
+		}
+		for k := start; k < len(fields); k += 2 {
+			e, err := row(fields[k])
+			if err != nil {
+				return err
+			}
+			if e == nil {
+				continue
+			}
+			value, err := parseNumber(fields[k+1])
+			if err != nil {
+				return fmt.Errorf("row %s: %w", fields[k], err)
+			}
+			if err := apply(e, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	section := ""
+	isInteger := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "*") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if line[0] != ' ' && line[0] != '\t' {
+			section = strings.ToUpper(fields[0])
+			switch section {
+			case "NAME", "ROWS", "COLUMNS", "RHS", "RANGES", "BOUNDS", "OBJSENSE":
+				if section == "OBJSENSE" && len(fields) > 1 {
+					p.isMinimization = !strings.HasPrefix(strings.ToUpper(fields[1]), "MAX")
+				}
+				continue
+			case "ENDATA":
+				return p.mpsTable(objective, ranged, ranges, bounds, integer)
+			default:
+				return nil, fmt.Errorf("unsupported MPS section %q", fields[0])
+			}
+		}
+
+		switch section {
+		case "OBJSENSE":
+			p.isMinimization = !strings.HasPrefix(strings.ToUpper(fields[0]), "MAX")
+		case "ROWS":
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("invalid ROWS line: %q", line)
+			}
+			var comparison Comparison
+			switch strings.ToUpper(fields[0]) {
+			case "N":
+				// Первая свободная строка - целевая функция, остальные игнорируются
+				if objectiveName == "" {
+					objectiveName = fields[1]
+				} else {
+					freeRows[fields[1]] = true
+				}
+				continue
+			case "L":
+				comparison = LessThanOrEqualTo
+			case "G":
+				comparison = GreaterThanOrEqualTo
+			case "E":
+				comparison = EqualTo
+			default:
+				return nil, fmt.Errorf("invalid row type %q", fields[0])
+			}
+			e := &linearExpression{
+				name:         fields[1],
+				coefficients: make(map[int]*fractional.Fraction),
+				constant:     fractional.ZeroValue,
+				comparison:   comparison,
+			}
+			rows[e.name] = e
+			p.constraints = append(p.constraints, e)
+		case "COLUMNS":
+			if len(fields) == 3 && strings.Trim(fields[1], "'") == "MARKER" {
+				switch strings.Trim(fields[2], "'") {
+				case "INTORG":
+					isInteger = true
+				case "INTEND":
+					isInteger = false
+				}
+				continue
+			}
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("invalid COLUMNS line: %q", line)
+			}
+			j := p.variable(fields[0])
+			if isInteger {
+				integer[j] = true
+			}
+			err := pairs(fields, 1, func(e *linearExpression, value *fractional.Fraction) error {
+				e.coefficients[j] = e.coefficient(j).Add(*value)
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		case "RHS", "RANGES":
+			// Имя набора значений необязательно в свободном формате
+			start := len(fields) % 2
+			err := pairs(fields, start, func(e *linearExpression, value *fractional.Fraction) error {
+				switch {
+				case section == "RANGES" && e == objective:
+					return fmt.Errorf("range on objective row %q", objectiveName)
+				case section == "RANGES":
+					if _, ok := ranges[e]; !ok {
+						ranged = append(ranged, e)
+					}
+					ranges[e] = value
+				case e == objective:
+					// Значение правой части для целевой функции задаёт её константу с обратным знаком
+					objective.constant = value.Reverse()
+				default:
+					e.constant = value
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		case "BOUNDS":
+			if err := p.parseMPSBound(fields, bounds, integer); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected line outside of section: %q", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("ENDATA is missing")
+}
+
+func (p *algebraicParser) parseMPSBound(fields []string, bounds map[int]*lpBound, integer map[int]bool) error {
+	if len(fields) < 2 {
+		return fmt.Errorf("invalid BOUNDS line: %q", strings.Join(fields, " "))
+	}
+	kind := strings.ToUpper(fields[0])
+	var name, value string
+	hasValue := true
+	switch kind {
+	case "FR", "MI", "PL", "BV":
+		// Для этих типов значение необязательно
+		hasValue = false
+		if len(fields) == 4 || (len(fields) == 3 && kind == "BV" && isNumber(fields[2])) {
+			hasValue = true
+		}
+	}
+	rest := fields[1:]
+	if hasValue {
+		if len(rest) == 3 {
+			rest = rest[1:]
+		}
+		if len(rest) != 2 {
+			return fmt.Errorf("invalid BOUNDS line: %q", strings.Join(fields, " "))
+		}
+		name, value = rest[0], rest[1]
+	} else {
+		name = rest[len(rest)-1]
+	}
+
+	j := p.variable(name)
+	b, ok := bounds[j]
+	if !ok {
+		b = &lpBound{lower: fractional.ZeroValue}
+		bounds[j] = b
+	}
+	var v *fractional.Fraction
+	if hasValue && kind != "BV" {
+		var err error
+		v, err = parseNumber(value)
+		if err != nil && !errors.Is(err, errInfinite) {
+			return fmt.Errorf("bound on %s: %w", name, err)
+		}
+	}
+
+	switch kind {
+	case "UP":
+		b.upper = v
+	case "LO":
+		b.lower = v
+	case "FX":
+		b.lower, b.upper = v, v
+	case "FR":
+		b.lower, b.upper = nil, nil
+	case "MI":
+		b.lower = nil
+	case "PL":
+		b.upper = nil
+	case "BV":
+		b.lower, b.upper = fractional.ZeroValue, fractional.OneValue
+		integer[j] = true
+	case "LI":
+		b.lower = v
+		integer[j] = true
+	case "UI":
+		b.upper = v
+		integer[j] = true
+	default:
+		return fmt.Errorf("unsupported bound type %q", fields[0])
+	}
+	return nil
+}
+
+// mpsTable превращает диапазоны в дополнительные ограничения и собирает таблицу
+func (p *algebraicParser) mpsTable(objective *linearExpression, ranged []*linearExpression,
+	ranges map[*linearExpression]*fractional.Fraction, bounds map[int]*lpBound, integer map[int]bool) (*Table, error) {
+	for _, e := range ranged {
+		r := ranges[e].Abs()
+		other := &linearExpression{
+			name:         e.name + "_range",
+			coefficients: e.coefficients,
+		}
+		switch {
+		case e.comparison == LessThanOrEqualTo:
+			other.comparison, other.constant = GreaterThanOrEqualTo, e.constant.Subtract(*r)
+		case e.comparison == GreaterThanOrEqualTo:
+			other.comparison, other.constant = LessThanOrEqualTo, e.constant.Add(*r)
+		case ranges[e].LessThan(*fractional.ZeroValue):
+			e.comparison = LessThanOrEqualTo
+			other.comparison, other.constant = GreaterThanOrEqualTo, e.constant.Subtract(*r)
+		default:
+			e.comparison = GreaterThanOrEqualTo
+			other.comparison, other.constant = LessThanOrEqualTo, e.constant.Add(*r)
+		}
+		p.constraints = append(p.constraints, other)
+	}
+	p.appendBounds(objective, bounds)
+	return p.table(objective, integer), nil
+}
+
+// WriteMPS записывает задачу в формате MPS. При именах не длиннее восьми
+// символов результат соответствует фиксированному формату. Дроби, не
+// представимые конечной десятичной записью, выводятся в виде n/d
+func (t *Table) WriteMPS(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	const objectiveName = "obj"
+	rowName := func(i int) string {
+		if name := t.RowName(i); name != "" {
+			return name
+		}
+		return fmt.Sprintf("R%d", i+1)
+	}
+	comparisons := t.constraintComparisons()
+	objective := t.Objective()
+
+	fmt.Fprintln(bw, "NAME          KWALGOS")
+	fmt.Fprintln(bw, "OBJSENSE")
+	if t.IsMinimizationProblem {
+		fmt.Fprintln(bw, "    MIN")
+	} else {
+		fmt.Fprintln(bw, "    MAX")
+	}
+	fmt.Fprintln(bw, "ROWS")
+	fmt.Fprintf(bw, " N  %s\n", objectiveName)
+	for i := range t.Rows {
+		fmt.Fprintf(bw, " %s  %s\n", [...]string{"E", "L", "G"}[comparisons[i]], rowName(i))
+	}
+
+	fmt.Fprintln(bw, "COLUMNS")
+	isInteger := false
+	for j := range t.Cols - 1 {
+		if t.isInteger(j) != isInteger {
+			isInteger = t.isInteger(j)
+			marker := "'INTEND'"
+			if isInteger {
+				marker = "'INTORG'"
+			}
+			fmt.Fprintf(bw, "    %-8s  'MARKER'                 %s\n", "MARKER", marker)
+		}
+		name := t.VarName(j)
+		written := false
+		if objective[j].NotEqual(*fractional.ZeroValue) {
+			fmt.Fprintf(bw, "    %-8s  %-8s  %12s\n", name, objectiveName, formatNumber(objective[j]))
+			written = true
+		}
+		for i := range t.Rows {
+			if t.Matrix[i][j].NotEqual(*fractional.ZeroValue) {
+				fmt.Fprintf(bw, "    %-8s  %-8s  %12s\n", name, rowName(i), formatNumber(t.Matrix[i][j]))
+				written = true
+			}
+		}
+		// Столбец без ненулевых элементов всё равно объявляется
+		if !written {
+			fmt.Fprintf(bw, "    %-8s  %-8s  %12s\n", name, objectiveName, "0")
+		}
+	}
+	if isInteger {
+		fmt.Fprintf(bw, "    %-8s  'MARKER'                 'INTEND'\n", "MARKER")
+	}
+
+	fmt.Fprintln(bw, "RHS")
+	if constant := t.ObjectiveConstant(); constant.NotEqual(*fractional.ZeroValue) {
+		fmt.Fprintf(bw, "    %-8s  %-8s  %12s\n", "RHS", objectiveName, formatNumber(constant.Reverse()))
+	}
+	for i := range t.Rows {
+		if rhs := t.Matrix[i][t.Cols-1]; rhs.NotEqual(*fractional.ZeroValue) {
+			fmt.Fprintf(bw, "    %-8s  %-8s  %12s\n", "RHS", rowName(i), formatNumber(rhs))
+		}
+	}
+	fmt.Fprintln(bw, "ENDATA")
+	return bw.Flush()
+}
+
+func isNumber(s string) bool {
+	_, err := parseNumber(s)
+	return err == nil || errors.Is(err, errInfinite)
+}
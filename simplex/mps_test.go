@@ -0,0 +1,66 @@
+package simplex
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestMPSRoundTrip проверяет, что WriteMPS и ReadMPS сохраняют задачу,
+// в том числе направление оптимизации
+func TestMPSRoundTrip(t *testing.T) {
+	for _, source := range []string{
+		"2 2\n1 1 <= 4\n1 3 >= 6\n2 3 0 max\n",
+		"2 2\n1 1 <= 4\n1 3 >= 6\n2 3 0 min\n",
+		"3 3\n2 1 5 >= 12\n1 0 8 = 16\n5 2 1 >= 10\n6 1 4 0 min\n",
+	} {
+		table, err := Scan(strings.NewReader(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var mps bytes.Buffer
+		if err := table.WriteMPS(&mps); err != nil {
+			t.Fatal(err)
+		}
+		again, err := ReadMPS(&mps)
+		if err != nil {
+			t.Fatalf("%s: %s", source, err)
+		}
+		var written strings.Builder
+		if _, err := again.WriteTo(&written); err != nil {
+			t.Fatal(err)
+		}
+		if written.String() != source {
+			t.Errorf("expected\n%sgot\n%s", source, written.String())
+		}
+	}
+}
+
+func TestReadMPSDefaultSense(t *testing.T) {
+	source := "NAME TEST\nROWS\n N  COST\n G  C1\nCOLUMNS\n    X1  COST  1  C1  1\nRHS\n    RHS  C1  2\nENDATA\n"
+	table, err := ReadMPS(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !table.IsMinimizationProblem {
+		t.Error("a problem without OBJSENSE must be a minimization")
+	}
+}
+
+// TestReadMPSFreeRows проверяет, что значения дополнительных свободных строк
+// пропускаются, а не считаются ссылками на неизвестные строки
+func TestReadMPSFreeRows(t *testing.T) {
+	source := "NAME TEST\nROWS\n N  COST\n N  FREE\n L  C1\nCOLUMNS\n    X1  COST  1  FREE  3\n    X1  C1  1\n    X2  FREE  1  C1  1\n" +
+		"RHS\n    RHS  C1  2  FREE  5\nRANGES\n    RNG  FREE  1\nENDATA\n"
+	table, err := ReadMPS(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written strings.Builder
+	if _, err := table.WriteTo(&written); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "1 2\n1 1 <= 2\n1 0 0 min\n"; written.String() != expected {
+		t.Errorf("expected\n%sgot\n%s", expected, written.String())
+	}
+}
@@ -0,0 +1,711 @@
+package simplex
+
+import (
+	"errors"
+	"fmt"
+	"kw-algos/fractional"
+)
+
+var (
+	ErrInfeasible = errors.New("problem is infeasible")
+	ErrUnbounded  = errors.New("problem is unbounded")
+)
+
+type ReductionKind int
+
+const (
+	EmptyRow ReductionKind = iota
+	EmptyColumn
+	SingletonRow
+	FixedColumn
+	DuplicateRow
+	RedundantRow
+)
+
+func (k ReductionKind) String() string {
+	return [...]string{"empty row", "empty column", "singleton row", "fixed column", "duplicate row", "redundant row"}[k]
+}
+
+// Reduction описывает одно упрощение задачи. Row и Col - индексы строки и
+// столбца исходной таблицы (-1, если не относятся к упрощению), Value -
+// значение закреплённой переменной или полученная граница, Name - имя переменной
+type Reduction struct {
+	Kind       ReductionKind
+	Row        int
+	Col        int
+	Name       string
+	Comparison Comparison
+	Value      *fractional.Fraction
+}
+
+func (r Reduction) String() string {
+	s := r.Kind.String()
+	if r.Row != -1 {
+		s += fmt.Sprintf(" %d", r.Row+1)
+	}
+	if r.Col != -1 {
+		s += " (" + r.Name
+		if r.Value != nil {
+			s += fmt.Sprintf(" %s %s", &r.Comparison, r.Value)
+		}
+		s += ")"
+	}
+	return s
+}
+
+// Presolve хранит упрощённую задачу и список упрощений, по которым
+// Postsolve восстанавливает решение исходной задачи
+type Presolve struct {
+	Original   *Table
+	Reduced    *Table
+	Reductions []Reduction
+	cols       []int
+	fixed      map[int]*fractional.Fraction
+	unbounded  []int
+	name       func(int) string
+	// upperSources и lowerSources - строки исходной задачи, задавшие верхнюю
+	// и нижнюю границы каждой строки упрощённой задачи
+	upperSources, lowerSources []rowSource
+	columns                    []fixedColumn
+}
+
+// rowSource - строка исходной задачи, левая часть которой равна левой части
+// строки упрощённой задачи, умноженной на factor; row = -1, если строки нет
+type rowSource struct {
+	row    int
+	factor *fractional.Fraction
+}
+
+// times возвращает источник для строки, умноженной на a
+func (s rowSource) times(a *fractional.Fraction) rowSource {
+	if s.row == -1 {
+		return s
+	}
+	return rowSource{s.row, s.factor.Multiply(*a)}
+}
+
+// fixedColumn - закреплённая переменная и строки, задавшие границы x <= value
+// и x >= value, если такие есть
+type fixedColumn struct {
+	col          int
+	value        *fractional.Fraction
+	upper, lower rowSource
+}
+
+// presolver - рабочее состояние упрощения, индексы строк и столбцов исходные
+type presolver struct {
+	matrix      [][]*fractional.Fraction
+	rhs         []*fractional.Fraction
+	comparisons []Comparison
+	z           []*fractional.Fraction
+	constant    *fractional.Fraction
+	rowActive   []bool
+	colActive   []bool
+	// Границы переменных, nil в upper означает бесконечность;
+	// lowerRow и upperRow - строки, задавшие границы
+	lower, upper       []*fractional.Fraction
+	lowerRow, upperRow []rowSource
+	// upperSources и lowerSources - строки, задавшие правую часть строки
+	// при объединении дубликатов
+	upperSources, lowerSources []rowSource
+	fixed                      map[int]*fractional.Fraction
+	columns                    []fixedColumn
+	reductions                 []Reduction
+	// unbounded - пустые столбцы, улучшающие целевую функцию без ограничения
+	unbounded      []int
+	isMinimization bool
+	name           func(int) string
+}
+
+// Presolve упрощает задачу до приведения к канонической форме: удаляет
+// пустые строки и столбцы, заменяет строки с одной переменной границами,
+// закрепляет переменные, удаляет дублирующиеся и избыточные ограничения.
+// Если упрощения обнаруживают несовместность, возвращается ErrInfeasible.
+// Пустой столбец, по которому целевая функция растёт без ограничения,
+// закрепляется на нижней границе: задача неограниченна, только если
+// упрощённая задача совместна, это проверяет Unbounded после её решения
+func (t *Table) Presolve() (*Presolve, error) {
+	n := t.Cols - 1
+	comparisons := t.constraintComparisons()
+	p := &presolver{
+		matrix:         make([][]*fractional.Fraction, t.Rows),
+		rhs:            make([]*fractional.Fraction, t.Rows),
+		comparisons:    comparisons,
+		z:              t.Objective(),
+		constant:       t.ObjectiveConstant(),
+		rowActive:      make([]bool, t.Rows),
+		upperSources:   make([]rowSource, t.Rows),
+		lowerSources:   make([]rowSource, t.Rows),
+		colActive:      make([]bool, n),
+		lower:          make([]*fractional.Fraction, n),
+		upper:          make([]*fractional.Fraction, n),
+		lowerRow:       make([]rowSource, n),
+		upperRow:       make([]rowSource, n),
+		fixed:          make(map[int]*fractional.Fraction),
+		isMinimization: t.IsMinimizationProblem,
+		name:           t.VarName,
+	}
+	for i := range t.Rows {
+		p.matrix[i] = make([]*fractional.Fraction, n)
+		copy(p.matrix[i], t.Matrix[i][:n])
+		p.rhs[i] = t.Matrix[i][n]
+		p.rowActive[i] = true
+		p.upperSources[i], p.lowerSources[i] = rowSource{row: -1}, rowSource{row: -1}
+		p.setSource(i, comparisons[i], rowSource{i, fractional.OneValue})
+	}
+	for j := range n {
+		p.colActive[j] = true
+		p.lower[j] = fractional.ZeroValue
+		p.lowerRow[j], p.upperRow[j] = rowSource{row: -1}, rowSource{row: -1}
+	}
+
+	for changed := true; changed; {
+		var err error
+		changed = false
+		for _, step := range []func() (bool, error){p.reduceRows, p.reduceColumns, p.reduceDuplicates, p.reduceRedundant} {
+			var c bool
+			if c, err = step(); err != nil {
+				return nil, err
+			}
+			changed = changed || c
+		}
+	}
+	return p.result(t), nil
+}
+
+// Unbounded возвращает ErrUnbounded, если целевая функция может расти без
+// ограничения по пустому столбцу. Ответ верен, только если упрощённая задача
+// совместна: иначе несовместна и исходная
+func (ps *Presolve) Unbounded() error {
+	if len(ps.unbounded) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s can grow without limit", ErrUnbounded, ps.name(ps.unbounded[0]))
+}
+
+// Postsolve переводит значения переменных упрощённой задачи в значения
+// переменных исходной таблицы
+func (ps *Presolve) Postsolve(x []*fractional.Fraction) []*fractional.Fraction {
+	result := make([]*fractional.Fraction, ps.Original.Cols-1)
+	for j := range result {
+		result[j] = fractional.ZeroValue
+	}
+	for j, v := range ps.fixed {
+		result[j] = v
+	}
+	for k, j := range ps.cols {
+		if k < len(x) {
+			result[j] = x[k]
+		}
+	}
+	return result
+}
+
+// PostsolveCertificate переводит доказательство ответа упрощённой задачи в
+// доказательство для исходной. Если упрощённая задача совместна, а целевая
+// функция растёт по пустому столбцу, доказательством служит луч вдоль него
+func (ps *Presolve) PostsolveCertificate(c *Certificate) (*Certificate, error) {
+	switch {
+	case c.Farkas != nil:
+		zero := make([]*fractional.Fraction, ps.Original.Vars)
+		for j := range zero {
+			zero[j] = fractional.ZeroValue
+		}
+		farkas, err := ps.postsolveMultipliers(c.Farkas, zero)
+		if err != nil {
+			return nil, err
+		}
+		return &Certificate{Farkas: farkas}, nil
+	case c.Ray != nil:
+		return &Certificate{X: ps.Postsolve(c.X), Ray: ps.postsolveRay(c.Ray)}, nil
+	case len(ps.unbounded) > 0:
+		ray := ps.postsolveRay(nil)
+		ray[ps.unbounded[0]] = fractional.OneValue
+		return &Certificate{X: ps.Postsolve(c.X), Ray: ray}, nil
+	}
+	y, err := ps.PostsolveDual(c.Y)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{X: ps.Postsolve(c.X), Y: y}, nil
+}
+
+// postsolveRay переводит луч упрощённой задачи в луч исходной: закреплённые
+// переменные вдоль него не меняются
+func (ps *Presolve) postsolveRay(d []*fractional.Fraction) []*fractional.Fraction {
+	result := make([]*fractional.Fraction, ps.Original.Cols-1)
+	for j := range result {
+		result[j] = fractional.ZeroValue
+	}
+	for k, j := range ps.cols {
+		if k < len(d) {
+			result[j] = d[k]
+		}
+	}
+	return result
+}
+
+// PostsolveDual переводит двойственные оценки упрощённой задачи в оценки
+// исходной. Оценки строк упрощённой задачи переносятся на строки, задавшие
+// их правые части, а оценки строк закреплённых переменных подбираются так,
+// чтобы выполнялись двойственные ограничения этих переменных
+func (ps *Presolve) PostsolveDual(y []*fractional.Fraction) ([]*fractional.Fraction, error) {
+	o := ps.Original
+	result, err := ps.postsolveMultipliers(o.fromMaximized(y), o.maximizedObjective())
+	if err != nil {
+		return nil, err
+	}
+	return o.fromMaximized(result), nil
+}
+
+// postsolveMultipliers переводит множители строк упрощённой задачи на максимум
+// в множители строк исходной так, чтобы для каждой закреплённой переменной j
+// выполнялось y·A_j >= c_j, а при положительном значении - равенство; для
+// множителей Фаркаша c = 0. Переменные обрабатываются в обратном порядке закрепления: строки, на которые
+// опирается закреплённая переменная, не содержат закреплённых позже
+func (ps *Presolve) postsolveMultipliers(y, c []*fractional.Fraction) ([]*fractional.Fraction, error) {
+	o := ps.Original
+	result := make([]*fractional.Fraction, o.Rows)
+	for i := range result {
+		result[i] = fractional.ZeroValue
+	}
+	for r, v := range y {
+		if v.Equal(*fractional.ZeroValue) {
+			continue
+		}
+		source := ps.upperSources[r]
+		if v.LessThan(*fractional.ZeroValue) {
+			source = ps.lowerSources[r]
+		}
+		if source.row == -1 {
+			return nil, fmt.Errorf("multiplier %s of reduced row %d has the wrong sign", v, r+1)
+		}
+		value, _ := v.Divide(*source.factor)
+		result[source.row] = result[source.row].Add(*value)
+	}
+	for k := len(ps.columns) - 1; k >= 0; k-- {
+		f := ps.columns[k]
+		d := c[f.col].Subtract(*o.columnValue(f.col, result))
+		source := f.upper
+		switch {
+		case d.Equal(*fractional.ZeroValue):
+			continue
+		case d.LessThan(*fractional.ZeroValue):
+			// Нулевой переменной запас в двойственном ограничении не мешает
+			if f.value.Equal(*fractional.ZeroValue) {
+				continue
+			}
+			source = f.lower
+		}
+		if source.row == -1 {
+			return nil, fmt.Errorf("dual constraint for %s cannot be restored: c - y·A = %s", o.VarName(f.col), d)
+		}
+		value, _ := d.Divide(*source.factor)
+		result[source.row] = result[source.row].Add(*value)
+	}
+	return result, nil
+}
+
+// reduceRows удаляет пустые строки и переводит строки с одной переменной в границы
+func (p *presolver) reduceRows() (bool, error) {
+	changed := false
+	for i := range p.matrix {
+		if !p.rowActive[i] {
+			continue
+		}
+		col, count := -1, 0
+		for j, a := range p.matrix[i] {
+			if p.colActive[j] && a.NotEqual(*fractional.ZeroValue) {
+				col = j
+				count++
+			}
+		}
+		switch count {
+		case 0:
+			if !p.comparisons[i].holds(fractional.ZeroValue, p.rhs[i]) {
+				return false, fmt.Errorf("%w: row %d reduces to 0 %s %s", ErrInfeasible, i+1, &p.comparisons[i], p.rhs[i])
+			}
+			p.rowActive[i] = false
+			p.record(EmptyRow, i, -1, EqualTo, nil)
+			changed = true
+		case 1:
+			a := p.matrix[i][col]
+			value, _ := p.rhs[i].Divide(*a)
+			comparison := p.comparisons[i]
+			upper, lower := p.upperSources[i].times(a), p.lowerSources[i].times(a)
+			if a.LessThan(*fractional.ZeroValue) {
+				comparison = flipComparison(comparison)
+				upper, lower = lower, upper
+			}
+			p.rowActive[i] = false
+			changed = true
+			switch comparison {
+			case EqualTo:
+				p.record(SingletonRow, i, col, comparison, value)
+				if err := p.fix(col, value, upper, lower); err != nil {
+					return false, err
+				}
+				continue
+			case LessThanOrEqualTo:
+				if p.upper[col] == nil || value.LessThan(*p.upper[col]) {
+					p.upper[col], p.upperRow[col] = value, upper
+				}
+			case GreaterThanOrEqualTo:
+				if value.GreaterThan(*p.lower[col]) {
+					p.lower[col], p.lowerRow[col] = value, lower
+				}
+			}
+			p.record(SingletonRow, i, col, comparison, value)
+			if p.upper[col] != nil && p.upper[col].LessThan(*p.lower[col]) {
+				return false, fmt.Errorf("%w: bounds of %s are %s <= %s <= %s",
+					ErrInfeasible, p.name(col), p.lower[col], p.name(col), p.upper[col])
+			}
+		}
+	}
+	return changed, nil
+}
+
+// reduceColumns закрепляет переменные с совпадающими границами и пустые столбцы
+func (p *presolver) reduceColumns() (bool, error) {
+	changed := false
+	for j := range p.colActive {
+		if !p.colActive[j] {
+			continue
+		}
+		if p.upper[j] != nil && p.upper[j].Equal(*p.lower[j]) {
+			p.record(FixedColumn, -1, j, EqualTo, p.lower[j])
+			if err := p.fix(j, p.lower[j], rowSource{row: -1}, rowSource{row: -1}); err != nil {
+				return false, err
+			}
+			changed = true
+			continue
+		}
+		isEmpty := true
+		for i := range p.matrix {
+			if p.rowActive[i] && p.matrix[i][j].NotEqual(*fractional.ZeroValue) {
+				isEmpty = false
+				break
+			}
+		}
+		if !isEmpty {
+			continue
+		}
+		// Переменная входит только в целевую функцию и свои границы
+		improves := p.z[j].GreaterThan(*fractional.ZeroValue)
+		if p.isMinimization {
+			improves = p.z[j].LessThan(*fractional.ZeroValue)
+		}
+		value := p.lower[j]
+		if improves {
+			if p.upper[j] == nil {
+				// Неограниченность решается после проверки совместности остальных строк
+				p.unbounded = append(p.unbounded, j)
+			} else {
+				value = p.upper[j]
+			}
+		}
+		p.record(EmptyColumn, -1, j, EqualTo, value)
+		if err := p.fix(j, value, rowSource{row: -1}, rowSource{row: -1}); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// reduceDuplicates объединяет ограничения с пропорциональными левыми частями
+func (p *presolver) reduceDuplicates() (bool, error) {
+	changed := false
+	for i := range p.matrix {
+		if !p.rowActive[i] {
+			continue
+		}
+		for k := i + 1; k < len(p.matrix); k++ {
+			if !p.rowActive[k] || !p.rowActive[i] {
+				continue
+			}
+			factor := p.proportion(i, k)
+			if factor == nil {
+				continue
+			}
+			// Приводим строку k к левой части строки i
+			rhs, _ := p.rhs[k].Divide(*factor)
+			comparison := p.comparisons[k]
+			if factor.LessThan(*fractional.ZeroValue) {
+				comparison = flipComparison(comparison)
+			}
+			drop, err := p.mergeRows(i, comparison, rhs, rowSource{k, factor})
+			if err != nil {
+				return false, err
+			}
+			if drop {
+				p.rowActive[k] = false
+				p.record(DuplicateRow, k, -1, EqualTo, nil)
+				changed = true
+			}
+		}
+	}
+	return changed, nil
+}
+
+// mergeRows сравнивает строку i с ограничением "строка i comparison rhs",
+// полученным из строки source, при необходимости ужесточает строку i и
+// сообщает, можно ли удалить второе
+func (p *presolver) mergeRows(i int, comparison Comparison, rhs *fractional.Fraction, source rowSource) (bool, error) {
+	current := p.comparisons[i]
+	infeasible := fmt.Errorf("%w: row %d contradicts a duplicate row", ErrInfeasible, i+1)
+	switch {
+	case current == EqualTo:
+		if !comparison.holds(p.rhs[i], rhs) {
+			return false, infeasible
+		}
+		return true, nil
+	case comparison == EqualTo:
+		if !current.holds(rhs, p.rhs[i]) {
+			return false, infeasible
+		}
+		p.comparisons[i], p.rhs[i] = EqualTo, rhs
+		p.setSource(i, EqualTo, source)
+		return true, nil
+	case comparison == current:
+		if (current == LessThanOrEqualTo) == rhs.LessThan(*p.rhs[i]) {
+			p.rhs[i] = rhs
+			p.setSource(i, comparison, source)
+		}
+		return true, nil
+	}
+	// Ограничения противоположных знаков задают отрезок
+	lower, upper := p.rhs[i], rhs
+	if current == LessThanOrEqualTo {
+		lower, upper = rhs, p.rhs[i]
+	}
+	if upper.LessThan(*lower) {
+		return false, infeasible
+	}
+	if upper.Equal(*lower) {
+		p.comparisons[i], p.rhs[i] = EqualTo, lower
+		p.setSource(i, comparison, source)
+		return true, nil
+	}
+	return false, nil
+}
+
+// setSource запоминает source как строку, задавшую верхнюю (для <= и =) и
+// нижнюю (для >= и =) границу строки i
+func (p *presolver) setSource(i int, comparison Comparison, source rowSource) {
+	if comparison != GreaterThanOrEqualTo {
+		p.upperSources[i] = source
+	}
+	if comparison != LessThanOrEqualTo {
+		p.lowerSources[i] = source
+	}
+}
+
+// proportion возвращает множитель f, для которого строка k равна строке i,
+// умноженной на f, либо nil, если строки не пропорциональны
+func (p *presolver) proportion(i, k int) *fractional.Fraction {
+	var factor *fractional.Fraction
+	for j := range p.colActive {
+		if !p.colActive[j] {
+			continue
+		}
+		a, b := p.matrix[i][j], p.matrix[k][j]
+		if a.Equal(*fractional.ZeroValue) != b.Equal(*fractional.ZeroValue) {
+			return nil
+		}
+		if a.Equal(*fractional.ZeroValue) {
+			continue
+		}
+		f, _ := b.Divide(*a)
+		if factor == nil {
+			factor = f
+		} else if factor.NotEqual(*f) {
+			return nil
+		}
+	}
+	return factor
+}
+
+// reduceRedundant удаляет ограничения, выполненные при любых значениях
+// переменных в их границах, и обнаруживает невыполнимые
+func (p *presolver) reduceRedundant() (bool, error) {
+	changed := false
+	for i := range p.matrix {
+		if !p.rowActive[i] {
+			continue
+		}
+		minActivity, maxActivity := p.activity(i)
+		b := p.rhs[i]
+		var redundant, infeasible bool
+		switch p.comparisons[i] {
+		case LessThanOrEqualTo:
+			redundant = maxActivity != nil && !maxActivity.GreaterThan(*b)
+			infeasible = minActivity != nil && minActivity.GreaterThan(*b)
+		case GreaterThanOrEqualTo:
+			redundant = minActivity != nil && !minActivity.LessThan(*b)
+			infeasible = maxActivity != nil && maxActivity.LessThan(*b)
+		case EqualTo:
+			infeasible = (minActivity != nil && minActivity.GreaterThan(*b)) ||
+				(maxActivity != nil && maxActivity.LessThan(*b))
+		}
+		if infeasible {
+			return false, fmt.Errorf("%w: row %d cannot be satisfied within variable bounds", ErrInfeasible, i+1)
+		}
+		if redundant {
+			p.rowActive[i] = false
+			p.record(RedundantRow, i, -1, EqualTo, nil)
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// activity вычисляет наименьшее и наибольшее значения левой части строки
+// в границах переменных, nil означает бесконечность
+func (p *presolver) activity(i int) (*fractional.Fraction, *fractional.Fraction) {
+	minActivity, maxActivity := fractional.ZeroValue, fractional.ZeroValue
+	for j, a := range p.matrix[i] {
+		if !p.colActive[j] || a.Equal(*fractional.ZeroValue) {
+			continue
+		}
+		low, high := p.lower[j], p.upper[j]
+		if a.LessThan(*fractional.ZeroValue) {
+			low, high = high, low
+		}
+		if low == nil {
+			minActivity = nil
+		} else if minActivity != nil {
+			minActivity = minActivity.Add(*a.Multiply(*low))
+		}
+		if high == nil {
+			maxActivity = nil
+		} else if maxActivity != nil {
+			maxActivity = maxActivity.Add(*a.Multiply(*high))
+		}
+	}
+	return minActivity, maxActivity
+}
+
+// fix закрепляет переменную, перенося её вклад в правые части и целевую
+// функцию; upper и lower - строки, задавшие значение, иначе оно берётся
+// из совпадающих с ним границ
+func (p *presolver) fix(j int, value *fractional.Fraction, upper, lower rowSource) error {
+	if value.LessThan(*p.lower[j]) || (p.upper[j] != nil && value.GreaterThan(*p.upper[j])) {
+		return fmt.Errorf("%w: %s = %s violates its bounds", ErrInfeasible, p.name(j), value)
+	}
+	column := fixedColumn{col: j, value: value, upper: upper, lower: lower}
+	if lower.row == -1 && value.Equal(*p.lower[j]) {
+		column.lower = p.lowerRow[j]
+	}
+	if upper.row == -1 && p.upper[j] != nil && value.Equal(*p.upper[j]) {
+		column.upper = p.upperRow[j]
+	}
+	p.columns = append(p.columns, column)
+	for i := range p.matrix {
+		if p.rowActive[i] {
+			p.rhs[i] = p.rhs[i].Subtract(*p.matrix[i][j].Multiply(*value))
+		}
+	}
+	p.constant = p.constant.Add(*p.z[j].Multiply(*value))
+	p.colActive[j] = false
+	p.fixed[j] = value
+	return nil
+}
+
+func (p *presolver) record(kind ReductionKind, row, col int, comparison Comparison, value *fractional.Fraction) {
+	var name string
+	if col != -1 {
+		name = p.name(col)
+	}
+	p.reductions = append(p.reductions, Reduction{Kind: kind, Row: row, Col: col, Name: name, Comparison: comparison, Value: value})
+}
+
+// result собирает упрощённую таблицу: оставшиеся строки и по одной строке
+// на каждую нетривиальную границу переменной
+func (p *presolver) result(t *Table) *Presolve {
+	var cols []int
+	for j, active := range p.colActive {
+		if active {
+			cols = append(cols, j)
+		}
+	}
+
+	var matrix [][]*fractional.Fraction
+	var comparisons []Comparison
+	var rowNames []string
+	var upperSources, lowerSources []rowSource
+	addRow := func(coefficients []*fractional.Fraction, comparison Comparison, rhs *fractional.Fraction, name string, upper, lower rowSource) {
+		row := make([]*fractional.Fraction, 0, len(cols)+1)
+		for _, j := range cols {
+			row = append(row, coefficients[j])
+		}
+		matrix = append(matrix, append(row, rhs))
+		comparisons = append(comparisons, comparison)
+		rowNames = append(rowNames, name)
+		upperSources = append(upperSources, upper)
+		lowerSources = append(lowerSources, lower)
+	}
+	none := rowSource{row: -1}
+	for i := range p.matrix {
+		if p.rowActive[i] {
+			addRow(p.matrix[i], p.comparisons[i], p.rhs[i], t.RowName(i), p.upperSources[i], p.lowerSources[i])
+		}
+	}
+	for _, j := range cols {
+		unit := make([]*fractional.Fraction, len(p.colActive))
+		for k := range unit {
+			unit[k] = fractional.ZeroValue
+		}
+		unit[j] = fractional.OneValue
+		if p.lower[j].GreaterThan(*fractional.ZeroValue) {
+			addRow(unit, GreaterThanOrEqualTo, p.lower[j], t.RowName(p.lowerRow[j].row), none, p.lowerRow[j])
+		}
+		if p.upper[j] != nil {
+			addRow(unit, LessThanOrEqualTo, p.upper[j], t.RowName(p.upperRow[j].row), p.upperRow[j], none)
+		}
+	}
+
+	z := make([]*fractional.Fraction, len(cols))
+	var varNames []string
+	var integer []bool
+	for k, j := range cols {
+		z[k] = p.z[j]
+		if len(t.VarNames) > 0 {
+			varNames = append(varNames, t.VarName(j))
+		}
+		if len(t.Integer) > 0 {
+			integer = append(integer, t.isInteger(j))
+		}
+	}
+
+	reduced := NewTable(matrix, comparisons, z, t.IsMinimizationProblem)
+	reduced.setObjectiveConstant(p.constant)
+	reduced.VarNames = varNames
+	reduced.Integer = integer
+	if len(t.RowNames) > 0 {
+		reduced.RowNames = rowNames
+	}
+	return &Presolve{
+		Original:   t,
+		Reduced:    reduced,
+		Reductions: p.reductions,
+		cols:       cols,
+		fixed:      p.fixed,
+		unbounded:  p.unbounded,
+		name:       t.VarName,
+
+		upperSources: upperSources,
+		lowerSources: lowerSources,
+		columns:      p.columns,
+	}
+}
+
+func flipComparison(c Comparison) Comparison {
+	switch c {
+	case LessThanOrEqualTo:
+		return GreaterThanOrEqualTo
+	case GreaterThanOrEqualTo:
+		return LessThanOrEqualTo
+	}
+	return c
+}
@@ -0,0 +1,160 @@
+package simplex
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestPresolveEmptyColumn проверяет, что пустой столбец без верхней границы
+// не делает задачу неограниченной раньше проверки совместности остальных строк
+func TestPresolveEmptyColumn(t *testing.T) {
+	for _, c := range []struct {
+		source     string
+		infeasible bool
+	}{
+		{"max: x3;\nx1 + 2x2 <= 1;\n2x1 + x2 >= 4;\n", true},
+		{"max: x3;\nx1 + 2x2 <= 1;\n2x1 + x2 >= 1;\n", false},
+	} {
+		table, err := ScanAlgebraic(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		presolve, err := table.Presolve()
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		if s := presolve.Reductions[0].String(); s != "empty column (x3 = 0)" {
+			t.Errorf("%q: reduction %q", c.source, s)
+		}
+
+		reduced := presolve.Reduced
+		reduced.SetOutput(io.Discard)
+		reduced.ToCanonicalForm()
+		basis, err := reduced.ToBasis()
+		if err == nil {
+			m := New(basis)
+			m.MaxIterations = 100
+			err = m.DualMethod()
+		}
+		if c.infeasible {
+			if !errors.Is(err, ErrNoSolutions) {
+				t.Errorf("%q: expected the reduced problem to be infeasible, got %v", c.source, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		if err := presolve.Unbounded(); !errors.Is(err, ErrUnbounded) {
+			t.Errorf("%q: expected ErrUnbounded, got %v", c.source, err)
+		}
+	}
+}
+
+// TestReductionNames проверяет, что упрощения называют переменные их именами
+func TestReductionNames(t *testing.T) {
+	table, err := ScanAlgebraic(strings.NewReader("max: 3apples + 2pears;\nc1: apples + pears <= 4;\nc2: pears = 1;\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	presolve, err := table.Presolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reductions []string
+	for _, r := range presolve.Reductions {
+		reductions = append(reductions, r.String())
+	}
+	if s := strings.Join(reductions, "\n"); !strings.Contains(s, "singleton row 2 (pears = 1)") {
+		t.Errorf("reductions:\n%s", s)
+	}
+}
+
+// TestPostsolveDual проверяет, что двойственные оценки упрощённой задачи
+// переводятся в оценки, доказывающие оптимальность ответа исходной задачи
+func TestPostsolveDual(t *testing.T) {
+	for _, source := range []string{
+		"max: 3x + 2y + z + 4;\na: x + y + z <= 10;\nb: 2x + 2y + 2z <= 30;\nc: x <= 4;\nd: y = 2;\ne: 0x <= 5;\nf: x + z >= -3;\ng: x <= 6;\n",
+		"max: x1 + x2;\nc1: 5x1 + x2 <= 5;\nc2: -2x2 <= -10;\nc3: 5x1 <= 0;\n",
+		"min: 2x1 + 3x2 - x3;\nc1: x1 + x2 + x3 >= 4;\nc2: 2x1 + 2x2 + 2x3 <= 12;\nc3: -x3 >= -3;\nc4: x2 >= 1;\n",
+	} {
+		table, err := ScanAlgebraic(strings.NewReader(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		presolve, err := table.Presolve()
+		if err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		reduced := presolve.Reduced
+		reduced.SetOutput(io.Discard)
+		reduced.ToCanonicalForm()
+		basis, err := reduced.ToBasis()
+		if err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		m := New(basis)
+		m.MaxIterations = 100
+		if err := m.DualMethod(); err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		certificate, err := m.Verify()
+		if err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		y, err := presolve.PostsolveDual(certificate.Y)
+		if err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		if err := table.VerifyOptimal(presolve.Postsolve(certificate.X), y); err != nil {
+			t.Errorf("%q: %s\n%v", source, err, presolve.Reductions)
+		}
+	}
+}
+
+// TestPostsolveCertificate проверяет, что множители Фаркаша и луч упрощённой
+// задачи переводятся в доказательства для строк и переменных исходной
+func TestPostsolveCertificate(t *testing.T) {
+	for _, source := range []string{
+		"max: x3;\nx1 + 2x2 <= 1;\n2x1 + x2 >= 4;\n",
+		"max: x1 + x4;\nc1: x1 + 2x2 + x4 <= 1;\nc2: 2x1 + x2 >= 4;\nc3: x4 = 1;\nc4: 2x1 + 4x2 <= 6;\n",
+		"max: x3;\nx1 + 2x2 <= 1;\n2x1 + x2 >= 1;\n",
+		"max: x1 + x2;\nc1: x1 - x2 <= 1;\nc2: 3x1 - 3x2 <= 5;\nc3: x2 >= 2;\nc4: x3 = 4;\n",
+	} {
+		table, err := ScanAlgebraic(strings.NewReader(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		presolve, err := table.Presolve()
+		if err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		reduced := presolve.Reduced
+		reduced.SetOutput(io.Discard)
+		reduced.ToCanonicalForm()
+		basis, err := reduced.ToBasis()
+		if err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		m := New(basis)
+		m.MaxIterations = 100
+		if err := m.DualMethod(); err != nil && !errors.Is(err, ErrNoSolutions) {
+			t.Fatalf("%q: %s", source, err)
+		}
+		certificate, err := m.Certificate()
+		if err != nil {
+			t.Fatalf("%q: %s", source, err)
+		}
+		if certificate.Y != nil && presolve.Unbounded() == nil {
+			t.Fatalf("%q: expected no solutions, got %s", source, certificate)
+		}
+		if certificate, err = presolve.PostsolveCertificate(certificate); err == nil {
+			err = table.Verify(certificate)
+		}
+		if err != nil {
+			t.Errorf("%q: %s\n%v", source, err, presolve.Reductions)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package simplex
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"strings"
+)
+
+var ErrDenominatorSign = errors.New("denominator is not positive on the feasible set")
+
+// FractionalProgram - задача дробно-линейного программирования: оптимизировать
+// (c·x + c0) / (d·x + d0) при ограничениях Table. Числитель хранится в Z и
+// ObjectiveConstant таблицы, знаменатель должен быть положителен на допустимом множестве
+type FractionalProgram struct {
+	Table           *Table
+	Denominator     []*fractional.Fraction
+	DenominatorFree *fractional.Fraction
+}
+
+// ScanFractional считывает задачу в формате Scan, после строки числителя
+// (с max или min) следует строка знаменателя "d1 ... dn d0"
+func ScanFractional(r io.Reader) (*FractionalProgram, error) {
+	scanner := bufio.NewScanner(r)
+	var lines []string
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) < 3 {
+		return nil, fmt.Errorf("expected constraints, numerator and denominator lines")
+	}
+	t, err := Scan(strings.NewReader(strings.Join(lines[:len(lines)-1], "\n") + "\n"))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) != t.Vars+1 {
+		return nil, fmt.Errorf("denominator: expected %d coefficients and a constant", t.Vars)
+	}
+	fp := &FractionalProgram{Table: t}
+	for j := range t.Vars {
+		d, err := fractional.Parse(fields[j])
+		if err != nil {
+			return nil, err
+		}
+		fp.Denominator = append(fp.Denominator, d)
+	}
+	if fp.DenominatorFree, err = fractional.Parse(fields[t.Vars]); err != nil {
+		return nil, err
+	}
+	return fp, nil
+}
+
+// ToTable применяет преобразование Чарнса-Купера: y = t·x, t = 1/(d·x + d0).
+// Получается обычная задача оптимизации c·y + c0·t при A·y - b·t (знак) 0,
+// d·y + d0·t = 1, где последняя переменная - t
+func (fp *FractionalProgram) ToTable() *Table {
+	src := fp.Table
+	vars := src.Vars
+	matrix := make([][]*fractional.Fraction, 0, src.Rows+1)
+	for i := range src.Rows {
+		row := make([]*fractional.Fraction, vars+2)
+		copy(row, src.Matrix[i][:vars])
+		row[vars] = src.Matrix[i][src.Cols-1].Reverse()
+		row[vars+1] = fractional.ZeroValue
+		matrix = append(matrix, row)
+	}
+	normalization := make([]*fractional.Fraction, vars+2)
+	copy(normalization, fp.Denominator)
+	normalization[vars] = fp.DenominatorFree
+	normalization[vars+1] = fractional.OneValue
+	matrix = append(matrix, normalization)
+
+	comparisons := append(src.Comparisons(), EqualTo)
+	z := make([]*fractional.Fraction, vars+1)
+	copy(z, src.Z[:vars])
+	z[vars] = src.ObjectiveConstant()
+
+	t := NewTable(matrix, comparisons, z, src.IsMinimizationProblem)
+	for j := range vars {
+		t.VarNames = append(t.VarNames, "y"+strings.TrimPrefix(src.VarName(j), "x"))
+	}
+	t.VarNames = append(t.VarNames, "t")
+	for i := range src.Rows {
+		name := src.RowName(i)
+		if name == "" {
+			name = fmt.Sprintf("c%d", i+1)
+		}
+		t.RowNames = append(t.RowNames, name)
+	}
+	t.RowNames = append(t.RowNames, "norm")
+	t.out = src.out
+	return t
+}
+
+// Solve решает преобразованную задачу симплекс-методом и возвращает значения
+// исходных переменных x = y/t и оптимальное значение отношения
+func (fp *FractionalProgram) Solve() ([]*fractional.Fraction, *fractional.Fraction, error) {
+	t := fp.ToTable()
+	values, err := t.solve()
+	if err != nil {
+		return nil, nil, err
+	}
+	vars := fp.Table.Vars
+	scale := values[vars]
+	if scale.Equal(*fractional.ZeroValue) {
+		return nil, nil, fmt.Errorf("optimum is not attained: t = 0")
+	}
+
+	x := make([]*fractional.Fraction, vars)
+	for j := range x {
+		if x[j], err = values[j].Divide(*scale); err != nil {
+			return nil, nil, err
+		}
+	}
+	numerator := dot(fp.Table.Z[:vars], x).Add(*fp.Table.ObjectiveConstant())
+	denominator := dot(fp.Denominator, x).Add(*fp.DenominatorFree)
+	if !denominator.GreaterThan(*fractional.ZeroValue) {
+		return nil, nil, ErrDenominatorSign
+	}
+	ratio, err := numerator.Divide(*denominator)
+	if err != nil {
+		return nil, nil, err
+	}
+	return x, ratio, nil
+}
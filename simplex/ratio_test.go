@@ -0,0 +1,38 @@
+package simplex
+
+import (
+	"io"
+	"kw-algos/fractional"
+	"strings"
+	"testing"
+)
+
+// TestFractionalConstant проверяет, что свободный член числителя задачи на
+// минимум берётся в исходной постановке, а не со знаком ZFree
+func TestFractionalConstant(t *testing.T) {
+	for _, c := range []struct {
+		source, x, ratio string
+	}{
+		{"min: x + 2;\nx <= 3;\n", "3", "5/4"},
+		{"max: x + 2;\nx <= 3;\n", "0", "2"},
+		{"min: -x + 3;\nx <= 3;\n", "3", "0"},
+	} {
+		table, err := ScanAlgebraic(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		table.SetOutput(io.Discard)
+		fp := &FractionalProgram{
+			Table:           table,
+			Denominator:     []*fractional.Fraction{fractional.OneValue},
+			DenominatorFree: fractional.OneValue,
+		}
+		x, ratio, err := fp.Solve()
+		if err != nil {
+			t.Fatalf("%q: %s", c.source, err)
+		}
+		if x[0].String() != c.x || ratio.String() != c.ratio {
+			t.Errorf("%q: x = %s, ratio = %s, expected %s and %s", c.source, x[0], ratio, c.x, c.ratio)
+		}
+	}
+}
@@ -0,0 +1,440 @@
+package simplex_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// Эталонный решатель для малых задач: перебор всех базисов канонической
+// формы без симплекс-таблиц. Он медленный, но не имеет общего кода с
+// DualMethod, поэтому расхождение указывает на ошибку одного из них
+
+type outcome int
+
+const (
+	optimal outcome = iota
+	infeasible
+	unbounded
+)
+
+func (o outcome) String() string {
+	return [...]string{"optimal", "infeasible", "unbounded"}[o]
+}
+
+// answer - исход задачи и оптимальное значение целевой функции
+type answer struct {
+	outcome outcome
+	value   *fractional.Fraction
+}
+
+func (a answer) String() string {
+	if a.outcome == optimal {
+		return fmt.Sprintf("optimal, Z = %s", a.value)
+	}
+	return a.outcome.String()
+}
+
+func (a answer) equal(b answer) bool {
+	return a.outcome == b.outcome && (a.outcome != optimal || a.value.Equal(*b.value))
+}
+
+// lp - задача в исходной постановке, которую удобно уменьшать при поиске
+// минимального контрпримера
+type lp struct {
+	a           [][]*fractional.Fraction
+	comparisons []simplex.Comparison
+	b           []*fractional.Fraction
+	c           []*fractional.Fraction
+	minimize    bool
+}
+
+func (p *lp) table() *simplex.Table {
+	matrix := make([][]*fractional.Fraction, len(p.a))
+	for i, row := range p.a {
+		matrix[i] = append(append([]*fractional.Fraction(nil), row...), p.b[i])
+	}
+	return simplex.NewTable(matrix, p.comparisons, p.c, p.minimize)
+}
+
+func (p *lp) String() string {
+	var s strings.Builder
+	_, _ = p.table().WriteTo(&s)
+	return s.String()
+}
+
+func (p *lp) clone() *lp {
+	q := &lp{
+		comparisons: append([]simplex.Comparison(nil), p.comparisons...),
+		b:           append([]*fractional.Fraction(nil), p.b...),
+		c:           append([]*fractional.Fraction(nil), p.c...),
+		minimize:    p.minimize,
+	}
+	for _, row := range p.a {
+		q.a = append(q.a, append([]*fractional.Fraction(nil), row...))
+	}
+	return q
+}
+
+func integer(n int64) *fractional.Fraction {
+	return fraction(n, 1)
+}
+
+func fraction(n, d int64) *fractional.Fraction {
+	f, _ := fractional.New(n, d)
+	return f
+}
+
+// randomLP строит задачу без подгонки под исход: среди таких задач много
+// несовместных и неограниченных
+func randomLP(rng *rand.Rand, rows, vars int) *lp {
+	p := &lp{minimize: rng.Intn(2) == 0}
+	for range rows {
+		row := make([]*fractional.Fraction, vars)
+		for j := range row {
+			row[j] = integer(rng.Int63n(9) - 3)
+		}
+		p.a = append(p.a, row)
+		p.comparisons = append(p.comparisons, simplex.Comparison(rng.Intn(3)))
+		p.b = append(p.b, integer(rng.Int63n(16)-5))
+	}
+	for range vars {
+		p.c = append(p.c, integer(rng.Int63n(11)-5))
+	}
+	return p
+}
+
+// referenceSolve находит все вершины допустимого множества канонической
+// формы Ax = b, x >= 0 и выбирает лучшую. Задача неограничена, если есть
+// вершина и крайний луч d (вершина множества Ad = 0, Σd = 1, d >= 0), вдоль
+// которого целевая функция улучшается
+func referenceSolve(p *lp) answer {
+	a, c := canonical(p)
+	vertices := basicFeasibleSolutions(a, p.b)
+	if len(vertices) == 0 {
+		return answer{outcome: infeasible}
+	}
+
+	rayRows := append(append([][]*fractional.Fraction(nil), a...), make([]*fractional.Fraction, len(c)))
+	rayRHS := make([]*fractional.Fraction, len(rayRows))
+	for i := range rayRHS {
+		rayRHS[i] = fractional.ZeroValue
+	}
+	for j := range c {
+		rayRows[len(a)][j] = fractional.OneValue
+	}
+	rayRHS[len(a)] = fractional.OneValue
+	for _, d := range basicFeasibleSolutions(rayRows, rayRHS) {
+		if dot(c, d).GreaterThan(*fractional.ZeroValue) {
+			return answer{outcome: unbounded}
+		}
+	}
+
+	best := dot(c, vertices[0])
+	for _, x := range vertices[1:] {
+		if value := dot(c, x); value.GreaterThan(*best) {
+			best = value
+		}
+	}
+	if p.minimize {
+		best = best.Reverse()
+	}
+	return answer{outcome: optimal, value: best}
+}
+
+// canonical добавляет по дополнительной переменной на каждое неравенство и
+// возвращает матрицу ограничений и целевую функцию задачи на максимум
+func canonical(p *lp) ([][]*fractional.Fraction, []*fractional.Fraction) {
+	var slack []int
+	for i, comparison := range p.comparisons {
+		if comparison != simplex.EqualTo {
+			slack = append(slack, i)
+		}
+	}
+	a := make([][]*fractional.Fraction, len(p.a))
+	for i, row := range p.a {
+		a[i] = append([]*fractional.Fraction(nil), row...)
+		for _, k := range slack {
+			switch {
+			case k != i:
+				a[i] = append(a[i], fractional.ZeroValue)
+			case p.comparisons[i] == simplex.LessThanOrEqualTo:
+				a[i] = append(a[i], fractional.OneValue)
+			default:
+				a[i] = append(a[i], fractional.RevOneValue)
+			}
+		}
+	}
+	c := make([]*fractional.Fraction, len(a[0]))
+	for j := range c {
+		c[j] = fractional.ZeroValue
+		if j < len(p.c) {
+			c[j] = p.c[j]
+			if p.minimize {
+				c[j] = c[j].Reverse()
+			}
+		}
+	}
+	return a, c
+}
+
+// basicFeasibleSolutions исключает линейно зависимые строки [A | b] методом
+// Жордана-Гаусса, затем для каждого набора из rank столбцов решает систему
+// тем же методом и оставляет неотрицательные решения
+func basicFeasibleSolutions(a [][]*fractional.Fraction, b []*fractional.Fraction) [][]*fractional.Fraction {
+	n := len(a[0])
+	augmented := make([][]*fractional.Fraction, len(a))
+	for i := range a {
+		augmented[i] = append(append([]*fractional.Fraction(nil), a[i]...), b[i])
+	}
+	reduced, pivots := gaussJordan(augmented, n+1)
+	for _, col := range pivots {
+		if col == n {
+			// Строка 0 = b с ненулевой правой частью: система несовместна
+			return nil
+		}
+	}
+	reduced = reduced[:len(pivots)]
+
+	var solutions [][]*fractional.Fraction
+	var visit func(basis []int, next int)
+	visit = func(basis []int, next int) {
+		if len(basis) == len(reduced) {
+			if x, ok := basicSolution(reduced, basis, n); ok {
+				solutions = append(solutions, x)
+			}
+			return
+		}
+		for j := next; j < n; j++ {
+			visit(append(basis, j), j+1)
+		}
+	}
+	visit(nil, 0)
+	return solutions
+}
+
+// basicSolution решает систему для базиса и возвращает решение, если
+// базисная матрица невырождена и решение неотрицательно
+func basicSolution(rows [][]*fractional.Fraction, basis []int, n int) ([]*fractional.Fraction, bool) {
+	system := make([][]*fractional.Fraction, len(rows))
+	for i, row := range rows {
+		for _, j := range basis {
+			system[i] = append(system[i], row[j])
+		}
+		system[i] = append(system[i], row[n])
+	}
+	reduced, pivots := gaussJordan(system, len(basis))
+	if len(pivots) < len(basis) {
+		return nil, false
+	}
+	x := make([]*fractional.Fraction, n)
+	for j := range x {
+		x[j] = fractional.ZeroValue
+	}
+	for i, col := range pivots {
+		value := reduced[i][len(basis)]
+		if value.LessThan(*fractional.ZeroValue) {
+			return nil, false
+		}
+		x[basis[col]] = value
+	}
+	return x, true
+}
+
+// gaussJordan приводит матрицу к ступенчатому виду с единичными ведущими
+// элементами, выбирая их в первых cols столбцах, и возвращает номера ведущих столбцов
+func gaussJordan(matrix [][]*fractional.Fraction, cols int) ([][]*fractional.Fraction, []int) {
+	m := make([][]*fractional.Fraction, len(matrix))
+	for i := range matrix {
+		m[i] = append([]*fractional.Fraction(nil), matrix[i]...)
+	}
+	var pivots []int
+	for col := 0; col < cols && len(pivots) < len(m); col++ {
+		r := len(pivots)
+		k := r
+		for k < len(m) && m[k][col].Equal(*fractional.ZeroValue) {
+			k++
+		}
+		if k == len(m) {
+			continue
+		}
+		m[r], m[k] = m[k], m[r]
+		pivot := m[r][col]
+		for j := range m[r] {
+			m[r][j], _ = m[r][j].Divide(*pivot)
+		}
+		for i := range m {
+			if i == r || m[i][col].Equal(*fractional.ZeroValue) {
+				continue
+			}
+			factor := m[i][col]
+			for j := range m[i] {
+				m[i][j] = m[i][j].Subtract(*factor.Multiply(*m[r][j]))
+			}
+		}
+		pivots = append(pivots, col)
+	}
+	return m, pivots
+}
+
+// dualSolve решает задачу так же, как команда solve: канонический вид,
+// базис методом Жордана-Гаусса и двойственный симплекс-метод
+func dualSolve(p *lp) (answer, error) {
+	t := p.table()
+	t.SetOutput(io.Discard)
+	t.ToCanonicalForm()
+	basis, err := t.ToBasis()
+	if errors.Is(err, simplex.ErrNoSolutions) {
+		return answer{outcome: infeasible}, nil
+	}
+	if err != nil {
+		return answer{}, err
+	}
+	method := simplex.New(basis)
+	method.MaxIterations = 1000
+	if err := method.DualMethod(); err != nil && !errors.Is(err, simplex.ErrNoSolutions) {
+		return answer{}, err
+	}
+	switch {
+	case method.Ray != nil:
+		return answer{outcome: unbounded}, nil
+	case method.Face == nil:
+		return answer{outcome: infeasible}, nil
+	}
+	return answer{outcome: optimal, value: dot(p.c, method.Values()[:len(p.c)])}, nil
+}
+
+// mismatch сравнивает решатели и описывает расхождение
+func mismatch(p *lp) string {
+	expected := referenceSolve(p)
+	actual, err := dualSolve(p)
+	if err != nil {
+		return fmt.Sprintf("expected %s, DualMethod failed: %s", expected, err)
+	}
+	if !expected.equal(actual) {
+		return fmt.Sprintf("expected %s, DualMethod found %s", expected, actual)
+	}
+	return ""
+}
+
+// minimize уменьшает задачу, пока расхождение сохраняется: удаляет
+// ограничения и переменные, обнуляет коэффициенты и приближает их к нулю
+func minimize(p *lp, fails func(*lp) bool) *lp {
+	for changed := true; changed; {
+		changed = false
+		for _, q := range smaller(p) {
+			if fails(q) {
+				p, changed = q, true
+				break
+			}
+		}
+	}
+	return p
+}
+
+// smaller перечисляет задачи, которые на один шаг проще p
+func smaller(p *lp) []*lp {
+	var result []*lp
+	for i := range p.a {
+		if len(p.a) > 1 {
+			q := p.clone()
+			q.a = append(q.a[:i], q.a[i+1:]...)
+			q.comparisons = append(q.comparisons[:i], q.comparisons[i+1:]...)
+			q.b = append(q.b[:i], q.b[i+1:]...)
+			result = append(result, q)
+		}
+	}
+	for j := range p.c {
+		if len(p.c) > 1 {
+			q := p.clone()
+			for i := range q.a {
+				q.a[i] = append(q.a[i][:j], q.a[i][j+1:]...)
+			}
+			q.c = append(q.c[:j], q.c[j+1:]...)
+			result = append(result, q)
+		}
+	}
+	shrink := func(f *fractional.Fraction, set func(q *lp, f *fractional.Fraction)) {
+		if f.Equal(*fractional.ZeroValue) {
+			return
+		}
+		q := p.clone()
+		set(q, fractional.ZeroValue)
+		result = append(result, q)
+		if half := integer(f.Numerator() / 2); f.Denominator() == 1 && half.NotEqual(*fractional.ZeroValue) {
+			q := p.clone()
+			set(q, half)
+			result = append(result, q)
+		}
+	}
+	for i := range p.a {
+		for j := range p.a[i] {
+			shrink(p.a[i][j], func(q *lp, f *fractional.Fraction) { q.a[i][j] = f })
+		}
+		shrink(p.b[i], func(q *lp, f *fractional.Fraction) { q.b[i] = f })
+	}
+	for j := range p.c {
+		shrink(p.c[j], func(q *lp, f *fractional.Fraction) { q.c[j] = f })
+	}
+	return result
+}
+
+// TestReferenceSolver сверяет эталонный решатель с известными ответами
+func TestReferenceSolver(t *testing.T) {
+	for _, tc := range []struct {
+		source   string
+		expected answer
+	}{
+		{"2 2\n1 1 <= 4\n1 3 <= 6\n3 2 0 max\n", answer{optimal, integer(12)}},
+		{"2 2\n1 1 >= 2\n1 -1 <= 1\n1 2 0 min\n", answer{optimal, fraction(5, 2)}},
+		{"2 1\n1 <= 1\n1 >= 2\n1 0 max\n", answer{outcome: infeasible}},
+		{"1 2\n1 -1 <= 1\n1 1 0 max\n", answer{outcome: unbounded}},
+	} {
+		table, err := simplex.Scan(strings.NewReader(tc.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := &lp{c: table.Z, minimize: table.IsMinimizationProblem, comparisons: table.Comparisons()}
+		for i := range table.Rows {
+			p.a = append(p.a, table.Matrix[i][:table.Vars])
+			p.b = append(p.b, table.Matrix[i][table.Vars])
+		}
+		if actual := referenceSolve(p); !actual.equal(tc.expected) {
+			t.Errorf("%s: expected %s, got %s", tc.source, tc.expected, actual)
+		}
+	}
+}
+
+// TestDualMethodAgainstReference - дифференциальный тест: тысячи случайных
+// задач до 6 ограничений и 8 переменных решаются двойственным
+// симплекс-методом и перебором вершин. Для расхождения печатается
+// минимальная задача, на которой оно сохраняется
+func TestDualMethodAgainstReference(t *testing.T) {
+	n := 3000
+	if testing.Short() {
+		n = 300
+	}
+	rng := rand.New(rand.NewSource(49))
+	failures := 0
+	for k := range n {
+		// Большие задачи перебираются долго, поэтому их меньше
+		rows, vars := 1+rng.Intn(4), 1+rng.Intn(5)
+		if k%10 == 0 {
+			rows, vars = 1+rng.Intn(6), 1+rng.Intn(8)
+		}
+		p := randomLP(rng, rows, vars)
+		if mismatch(p) == "" {
+			continue
+		}
+		minimal := minimize(p, func(q *lp) bool { return mismatch(q) != "" })
+		t.Errorf("problem %d: %s\nminimal counterexample:\n%s", k, mismatch(minimal), minimal)
+		if failures++; failures == 5 {
+			t.Fatal("too many counterexamples")
+		}
+	}
+}
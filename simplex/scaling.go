@@ -0,0 +1,215 @@
+package simplex
+
+import (
+	"fmt"
+	"kw-algos/fractional"
+	"math"
+)
+
+type ScalingMethod int
+
+const (
+	GeometricMean ScalingMethod = iota
+	Equilibration
+)
+
+func (s ScalingMethod) String() string {
+	return [...]string{"geometric mean", "equilibration"}[s]
+}
+
+// maxScaleExponent ограничивает накопленные за все проходы множители строк и
+// столбцов, чтобы знаменатели дробей не переполнялись
+const maxScaleExponent = 30
+
+// Scaling хранит множители строк и столбцов: масштабированная задача имеет
+// матрицу R·A·C, правую часть R·b и целевую функцию C·c. Множители - степени
+// двойки, поэтому масштабирование точно и в дробях, и в числах float64
+type Scaling struct {
+	Method          ScalingMethod
+	RowFactors      []*fractional.Fraction
+	ColFactors      []*fractional.Fraction
+	RatioBefore     float64
+	RatioAfter      float64
+	objectiveBefore float64
+	objectiveAfter  float64
+}
+
+// Scale масштабирует строки и столбцы Matrix и Z до приведения к канонической
+// форме. Для метода среднего геометрического выполняется passes проходов по
+// строкам и столбцам, уравновешивание выполняется за один проход
+func (t *Table) Scale(method ScalingMethod, passes int) *Scaling {
+	n := t.Cols - 1
+	s := &Scaling{
+		Method:     method,
+		RowFactors: make([]*fractional.Fraction, t.Rows),
+		ColFactors: make([]*fractional.Fraction, n),
+	}
+	for i := range s.RowFactors {
+		s.RowFactors[i] = fractional.OneValue
+	}
+	for j := range s.ColFactors {
+		s.ColFactors[j] = fractional.OneValue
+	}
+	s.RatioBefore = t.scalingRatio()
+	s.objectiveBefore = ratio(t.Z[:n])
+	if method == Equilibration {
+		passes = 1
+	}
+
+	// Показатели степеней двойки в RowFactors и ColFactors
+	rowExponents, colExponents := make([]int, t.Rows), make([]int, n)
+	for range passes {
+		matrix, z := t.CopyMatrix(), t.CopyZ()
+		rowFactors := append([]*fractional.Fraction(nil), s.RowFactors...)
+		colFactors := append([]*fractional.Fraction(nil), s.ColFactors...)
+		rows, cols := append([]int(nil), rowExponents...), append([]int(nil), colExponents...)
+		before := t.scalingRatio()
+
+		for i := range t.Rows {
+			exponent := limitExponent(rowExponents[i], scaleExponent(method, t.Matrix[i][:n]))
+			if exponent == 0 {
+				continue
+			}
+			factor := powerOfTwo(exponent)
+			for j := range t.Cols {
+				t.Matrix[i][j] = t.Matrix[i][j].Multiply(*factor)
+			}
+			s.RowFactors[i] = s.RowFactors[i].Multiply(*factor)
+			rowExponents[i] += exponent
+		}
+		column := make([]*fractional.Fraction, t.Rows)
+		for j := range n {
+			for i := range t.Rows {
+				column[i] = t.Matrix[i][j]
+			}
+			exponent := limitExponent(colExponents[j], scaleExponent(method, column))
+			if exponent == 0 {
+				continue
+			}
+			factor := powerOfTwo(exponent)
+			for i := range t.Rows {
+				t.Matrix[i][j] = t.Matrix[i][j].Multiply(*factor)
+			}
+			t.Z[j] = t.Z[j].Multiply(*factor)
+			s.ColFactors[j] = s.ColFactors[j].Multiply(*factor)
+			colExponents[j] += exponent
+		}
+
+		// Округление до степеней двойки может ухудшить разброс, такой проход отменяется
+		if method == GeometricMean && t.scalingRatio() >= before {
+			t.Matrix, t.Z = matrix, z
+			s.RowFactors, s.ColFactors = rowFactors, colFactors
+			rowExponents, colExponents = rows, cols
+			break
+		}
+	}
+
+	s.RatioAfter = t.scalingRatio()
+	s.objectiveAfter = ratio(t.Z[:n])
+	return s
+}
+
+// Unscale переводит значения переменных масштабированной задачи в исходные: x = C·x'
+func (s *Scaling) Unscale(x []*fractional.Fraction) []*fractional.Fraction {
+	return multiply(x, s.ColFactors)
+}
+
+// UnscaleDual переводит двойственные оценки масштабированной задачи в исходные: y = R·y'
+func (s *Scaling) UnscaleDual(y []*fractional.Fraction) []*fractional.Fraction {
+	return multiply(y, s.RowFactors)
+}
+
+// UnscaleCertificate переводит доказательство ответа масштабированной задачи
+// в доказательство для исходной: точки и лучи умножаются на C, двойственные
+// оценки и множители Фаркаша - на R
+func (s *Scaling) UnscaleCertificate(c *Certificate) *Certificate {
+	unscaled := &Certificate{}
+	if c.X != nil {
+		unscaled.X = s.Unscale(c.X)
+	}
+	if c.Y != nil {
+		unscaled.Y = s.UnscaleDual(c.Y)
+	}
+	if c.Farkas != nil {
+		unscaled.Farkas = s.UnscaleDual(c.Farkas)
+	}
+	if c.Ray != nil {
+		unscaled.Ray = s.Unscale(c.Ray)
+	}
+	return unscaled
+}
+
+func (s *Scaling) String() string {
+	return fmt.Sprintf("%s scaling: max|a|/min|a| %.4g -> %.4g, objective %.4g -> %.4g",
+		s.Method, s.RatioBefore, s.RatioAfter, s.objectiveBefore, s.objectiveAfter)
+}
+
+// scaleExponent подбирает показатель степени двойки, ближайшей к величине,
+// обратной среднему геометрическому наибольшего и наименьшего по модулю
+// элементов строки или столбца, либо обратной наибольшему элементу
+func scaleExponent(method ScalingMethod, values []*fractional.Fraction) int {
+	smallest, largest := math.Inf(1), 0.0
+	for _, v := range values {
+		if a := math.Abs(v.Float64()); a != 0 {
+			smallest = math.Min(smallest, a)
+			largest = math.Max(largest, a)
+		}
+	}
+	if largest == 0 {
+		return 0
+	}
+	target := largest
+	if method == GeometricMean {
+		target = math.Sqrt(smallest * largest)
+	}
+	return -int(math.Round(math.Log2(target)))
+}
+
+// limitExponent урезает показатель очередного множителя так, чтобы
+// накопленный показатель total + exponent не выходил за maxScaleExponent
+func limitExponent(total, exponent int) int {
+	return max(-maxScaleExponent, min(maxScaleExponent, total+exponent)) - total
+}
+
+// scalingRatio - отношение наибольшего элемента матрицы к наименьшему по модулю
+func (t *Table) scalingRatio() float64 {
+	values := make([]*fractional.Fraction, 0, t.Rows*(t.Cols-1))
+	for i := range t.Rows {
+		values = append(values, t.Matrix[i][:t.Cols-1]...)
+	}
+	return ratio(values)
+}
+
+func ratio(values []*fractional.Fraction) float64 {
+	smallest, largest := math.Inf(1), 0.0
+	for _, v := range values {
+		if a := math.Abs(v.Float64()); a != 0 {
+			smallest = math.Min(smallest, a)
+			largest = math.Max(largest, a)
+		}
+	}
+	if largest == 0 {
+		return 1
+	}
+	return largest / smallest
+}
+
+func powerOfTwo(exponent int) *fractional.Fraction {
+	if exponent < 0 {
+		f, _ := fractional.New(1, int64(1)<<-exponent)
+		return f
+	}
+	f, _ := fractional.New(int64(1)<<exponent, 1)
+	return f
+}
+
+func multiply(values, factors []*fractional.Fraction) []*fractional.Fraction {
+	result := make([]*fractional.Fraction, len(values))
+	for i, v := range values {
+		result[i] = v
+		if i < len(factors) {
+			result[i] = v.Multiply(*factors[i])
+		}
+	}
+	return result
+}
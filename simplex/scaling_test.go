@@ -0,0 +1,87 @@
+package simplex_test
+
+import (
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"strings"
+	"testing"
+)
+
+// solveScaled решает задачу, масштабированную методом method, и возвращает
+// доказательство в единицах исходной задачи; method < 0 - без масштабирования
+func solveScaled(t *testing.T, source string, method simplex.ScalingMethod) *simplex.Certificate {
+	t.Helper()
+	table, err := simplex.ScanAlgebraic(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.SetOutput(io.Discard)
+	var scaling *simplex.Scaling
+	if method >= 0 {
+		scaling = table.Scale(method, 4)
+	}
+	table.ToCanonicalForm()
+	basis, err := table.ToBasis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := simplex.New(basis)
+	m.MaxIterations = 1000
+	if err := m.DualMethod(); err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := m.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scaling != nil {
+		certificate = scaling.UnscaleCertificate(certificate)
+	}
+	return certificate
+}
+
+// TestScalingCertificate проверяет, что после масштабирования ответ и
+// двойственные оценки в исходных единицах совпадают с ответом без него
+func TestScalingCertificate(t *testing.T) {
+	for _, source := range []string{
+		"max: x1 + 3x2;\n1024x1 + 2048x2 <= 4096;\nx1 + x2 <= 3;\n",
+		"min: 3x1 + 5x2;\n1/64x1 + 1/32x2 >= 1/8;\n16x1 + x2 >= 32;\n",
+		"max: 1000x1 + x2 + 1/100x3;\n2000x1 + 3x2 <= 10000;\n1/10x2 + 1/1000x3 <= 1;\nx1 + x3 = 3;\n",
+	} {
+		expected := solveScaled(t, source, -1)
+		for _, method := range []simplex.ScalingMethod{simplex.GeometricMean, simplex.Equilibration} {
+			actual := solveScaled(t, source, method)
+			if !equalVectors(actual.X, expected.X) || !equalVectors(actual.Y, expected.Y) {
+				t.Errorf("%s scaling of\n%s: %s, without scaling %s", method, source, actual, expected)
+			}
+		}
+	}
+}
+
+// TestScalingLimit проверяет, что множители, накопленные за несколько
+// проходов, не превышают 2^30 по модулю показателя
+func TestScalingLimit(t *testing.T) {
+	tiny, _ := fractional.New(1, int64(1)<<50)
+	one := fractional.OneValue
+	table := simplex.NewTable([][]*fractional.Fraction{{tiny, tiny, tiny}, {one, one, one}},
+		[]simplex.Comparison{simplex.LessThanOrEqualTo, simplex.LessThanOrEqualTo}, []*fractional.Fraction{one, one}, false)
+	scaling := table.Scale(simplex.GeometricMean, 4)
+	for _, factor := range append(scaling.RowFactors, scaling.ColFactors...) {
+		if factor.Numerator() > 1<<30 || factor.Denominator() > 1<<30 {
+			t.Errorf("factor %s exceeds 2^30: rows %v, columns %v", factor, scaling.RowFactors, scaling.ColFactors)
+		}
+	}
+}
+
+func equalVectors(a, b []*fractional.Fraction) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if a[k].NotEqual(*b[k]) {
+			return false
+		}
+	}
+	return true
+}
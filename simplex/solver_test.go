@@ -0,0 +1,189 @@
+package simplex_test
+
+import (
+	"errors"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/gen"
+	"kw-algos/simplex"
+	"math/rand"
+	"testing"
+)
+
+// problems строит n случайных задач с малыми коэффициентами и всеми
+// видами ограничений; seed фиксирован, чтобы падения воспроизводились
+func problems(n int, seed int64) []*simplex.Table {
+	rng := rand.New(rand.NewSource(seed))
+	var tables []*simplex.Table
+	for len(tables) < n {
+		o := gen.DefaultOptions
+		o.Rows, o.Vars = 1+rng.Intn(4), 1+rng.Intn(4)
+		o.Equal = 1
+		o.Minimize = rng.Intn(2) == 0
+		o.Outcome = gen.Outcome(rng.Intn(5))
+		o.Attempts = 50
+		t, _, err := gen.Generate(o, rng)
+		if err == nil {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+func copyTable(t *simplex.Table, rows []int) *simplex.Table {
+	comparisons := t.Comparisons()
+	matrix := make([][]*fractional.Fraction, len(rows))
+	c := make([]simplex.Comparison, len(rows))
+	for k, i := range rows {
+		matrix[k] = append([]*fractional.Fraction(nil), t.Matrix[i]...)
+		c[k] = comparisons[i]
+	}
+	return simplex.NewTable(matrix, c, append([]*fractional.Fraction(nil), t.Z...), t.IsMinimizationProblem)
+}
+
+func identity(n int) []int {
+	rows := make([]int, n)
+	for i := range rows {
+		rows[i] = i
+	}
+	return rows
+}
+
+// solve решает задачу и возвращает проверенное доказательство ответа
+func solve(t *simplex.Table) (*simplex.Certificate, error) {
+	t.SetOutput(io.Discard)
+	t.ToCanonicalForm()
+	basis, err := t.ToBasis()
+	if err != nil {
+		return nil, err
+	}
+	method := simplex.New(basis)
+	method.Rule = simplex.Bland
+	method.MaxIterations = 1000
+	if err := method.DualMethod(); err != nil && !errors.Is(err, simplex.ErrNoSolutions) {
+		return nil, err
+	}
+	return method.Verify()
+}
+
+func dot(a, b []*fractional.Fraction) *fractional.Fraction {
+	s := fractional.ZeroValue
+	for k := range a {
+		s = s.Add(*a[k].Multiply(*b[k]))
+	}
+	return s
+}
+
+// TestWeakDuality проверяет, что значение двойственной задачи на найденных
+// оценках y ограничивает значение целевой функции в каждой вершине
+// допустимого множества: c·x <= b·y для задачи на максимум и c·x >= b·y на минимум
+func TestWeakDuality(t *testing.T) {
+	checked := 0
+	for k, problem := range problems(300, 1) {
+		certificate, err := solve(copyTable(problem, identity(problem.Rows)))
+		if err != nil {
+			continue
+		}
+		if certificate.Y == nil {
+			continue
+		}
+		b := make([]*fractional.Fraction, problem.Rows)
+		for i := range b {
+			b[i] = problem.Matrix[i][problem.Cols-1]
+		}
+		bound := dot(b, certificate.Y)
+
+		canonical := copyTable(problem, identity(problem.Rows))
+		canonical.SetOutput(io.Discard)
+		canonical.ToCanonicalForm()
+		polyhedron, err := canonical.Vertices()
+		if err != nil {
+			t.Fatalf("problem %d: %s", k, err)
+		}
+		for _, v := range polyhedron.Vertices {
+			value := dot(problem.Z, v.X[:problem.Vars])
+			if problem.IsMinimizationProblem && value.LessThan(*bound) || !problem.IsMinimizationProblem && value.GreaterThan(*bound) {
+				t.Errorf("problem %d: vertex %v has c·x = %s beyond b·y = %s\n%s", k, v.X, value, bound, problem)
+			}
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Fatal("no problem with an optimal solution was generated")
+	}
+}
+
+// TestRowPermutation проверяет, что перестановка ограничений не меняет исход
+// задачи и оптимальное значение целевой функции
+func TestRowPermutation(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for k, problem := range problems(300, 2) {
+		outcome, value, err := gen.Classify(copyTable(problem, identity(problem.Rows)))
+		if err != nil {
+			t.Fatalf("problem %d: %s", k, err)
+		}
+		rows := rng.Perm(problem.Rows)
+		permuted, permutedValue, err := gen.Classify(copyTable(problem, rows))
+		if err != nil {
+			t.Fatalf("problem %d, rows %v: %s", k, rows, err)
+		}
+		if permuted != outcome {
+			t.Errorf("problem %d: %s, after permuting rows %v: %s\n%s", k, outcome, rows, permuted, problem)
+			continue
+		}
+		if value != nil && value.NotEqual(*permutedValue) {
+			t.Errorf("problem %d: Z = %s, after permuting rows %v: Z = %s\n%s", k, value, rows, permutedValue, problem)
+		}
+	}
+}
+
+// TestRestoredCertificate проверяет, что после упрощения и масштабирования
+// доказательство ответа - оптимальности, несовместности или неограниченности -
+// переведённое в переменные и ограничения исходной задачи, проходит проверку
+// по ней самой
+func TestRestoredCertificate(t *testing.T) {
+	checked := 0
+	for k, problem := range problems(300, 3) {
+		for _, scale := range []bool{false, true} {
+			original := copyTable(problem, identity(problem.Rows))
+			reduced := original.Clone()
+			presolve, err := reduced.Presolve()
+			if err != nil {
+				continue
+			}
+			reduced = presolve.Reduced
+			var scaling *simplex.Scaling
+			if scale {
+				scaling = reduced.Scale(simplex.GeometricMean, 4)
+			}
+			reduced.SetOutput(io.Discard)
+			reduced.ToCanonicalForm()
+			basis, err := reduced.ToBasis()
+			if err != nil {
+				continue
+			}
+			method := simplex.New(basis)
+			method.MaxIterations = 1000
+			if err := method.DualMethod(); err != nil && !errors.Is(err, simplex.ErrNoSolutions) {
+				t.Fatalf("problem %d: %s", k, err)
+			}
+			certificate, err := method.Certificate()
+			if err != nil {
+				t.Fatalf("problem %d: %s", k, err)
+			}
+			if scaling != nil {
+				certificate = scaling.UnscaleCertificate(certificate)
+			}
+			if certificate, err = presolve.PostsolveCertificate(certificate); err == nil {
+				err = original.Verify(certificate)
+			}
+			if err != nil {
+				t.Errorf("problem %d, scaling %v: %s\n%s%v", k, scale, err, problem, presolve.Reductions)
+			}
+			checked++
+		}
+	}
+	if checked == 0 {
+		t.Fatal("no problem reached the simplex method after presolve")
+	}
+}
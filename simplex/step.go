@@ -0,0 +1,289 @@
+package simplex
+
+import (
+	"errors"
+	"fmt"
+	"kw-algos/fractional"
+)
+
+var ErrZeroPivot = errors.New("pivot element is zero")
+
+// Phase - состояние таблицы при пошаговом решении
+type Phase int
+
+const (
+	// DualPhase - есть отрицательный свободный член, шаг двойственного симплекс-метода
+	DualPhase Phase = iota
+	// PrimalPhase - план допустим, но в Z-строке есть отрицательные оценки
+	PrimalPhase
+	Optimal
+	// Unsolvable - в разрешающей строке или столбце нет подходящих элементов
+	Unsolvable
+)
+
+func (p Phase) String() string {
+	return [...]string{"dual simplex", "simplex", "optimal", "no solutions"}[p]
+}
+
+// Candidate - возможный разрешающий элемент и его симплексное отношение
+type Candidate struct {
+	Row, Col int
+	Ratio    *fractional.Fraction
+}
+
+// Pivot - выполненный шаг: разрешающий элемент и таблица после него
+type Pivot struct {
+	Row, Col int
+	Table    *Table
+	Warnings []string
+}
+
+// Session - пошаговое решение, в котором разрешающий элемент выбирает
+// пользователь. Таблицы до каждого шага сохраняются для отмены
+type Session struct {
+	Method  *Method
+	History []Pivot
+	tables  []*Table
+}
+
+// NewSession начинает пошаговое решение с таблицы, приведённой к базису методом Жордана-Гаусса
+func NewSession(t *Table) *Session {
+	convertZString(t)
+	return &Session{Method: New(t)}
+}
+
+// Phase определяет, какой шаг нужен текущей таблице. Строка с отрицательным
+// свободным членом без отрицательных элементов или столбец с отрицательной
+// оценкой без положительных элементов означают, что решений нет
+func (s *Session) Phase() Phase {
+	t := s.Method.Table
+	if s.negativeRow() != -1 {
+		for i := range t.Rows {
+			if t.Matrix[i][t.Cols-1].LessThan(*fractional.ZeroValue) && !s.hasCandidate(i, -1) {
+				return Unsolvable
+			}
+		}
+		return DualPhase
+	}
+	if s.negativeColumn() != -1 {
+		for j, z := range t.Z {
+			if z.LessThan(*fractional.ZeroValue) && !s.hasCandidate(-1, j) {
+				return Unsolvable
+			}
+		}
+		return PrimalPhase
+	}
+	return Optimal
+}
+
+// hasCandidate сообщает, есть ли среди кандидатов элемент строки row или столбца col
+func (s *Session) hasCandidate(row, col int) bool {
+	for _, c := range s.Candidates() {
+		if c.Row == row || c.Col == col {
+			return true
+		}
+	}
+	return false
+}
+
+// negativeRow - строка с наименьшим отрицательным свободным членом или -1
+func (s *Session) negativeRow() int {
+	t := s.Method.Table
+	row := -1
+	for i := range t.Rows {
+		if t.Matrix[i][t.Cols-1].LessThan(*fractional.ZeroValue) &&
+			(row == -1 || t.Matrix[i][t.Cols-1].LessThan(*t.Matrix[row][t.Cols-1])) {
+			row = i
+		}
+	}
+	return row
+}
+
+// negativeColumn - столбец с наименьшей отрицательной оценкой или -1
+func (s *Session) negativeColumn() int {
+	col := -1
+	for j, z := range s.Method.Table.Z {
+		if z.LessThan(*fractional.ZeroValue) && (col == -1 || z.LessThan(*s.Method.Table.Z[col])) {
+			col = j
+		}
+	}
+	return col
+}
+
+// Candidates перечисляет допустимые разрешающие элементы текущего шага: для
+// двойственного метода - отрицательные элементы строк с отрицательным
+// свободным членом, для обычного - положительные элементы столбцов с
+// отрицательной оценкой. Для каждого указано отношение CO
+func (s *Session) Candidates() []Candidate {
+	t := s.Method.Table
+	var candidates []Candidate
+	if s.negativeRow() != -1 {
+		for i := range t.Rows {
+			if !t.Matrix[i][t.Cols-1].LessThan(*fractional.ZeroValue) {
+				continue
+			}
+			for j := range t.Cols - 1 {
+				if t.Matrix[i][j].LessThan(*fractional.ZeroValue) {
+					ratio, _ := t.Z[j].Divide(*t.Matrix[i][j])
+					candidates = append(candidates, Candidate{i, j, ratio.Abs()})
+				}
+			}
+		}
+		return candidates
+	}
+	for j, z := range t.Z {
+		if !z.LessThan(*fractional.ZeroValue) {
+			continue
+		}
+		for i := range t.Rows {
+			if t.Matrix[i][j].GreaterThan(*fractional.ZeroValue) {
+				ratio, _ := t.Matrix[i][t.Cols-1].Divide(*t.Matrix[i][j])
+				candidates = append(candidates, Candidate{i, j, ratio})
+			}
+		}
+	}
+	return candidates
+}
+
+// Suggest возвращает разрешающий элемент, который выбрал бы DualMethod.
+// В оптимальной таблице и в таблице без решений шага нет
+func (s *Session) Suggest() (Candidate, bool) {
+	if phase := s.Phase(); phase != DualPhase && phase != PrimalPhase {
+		return Candidate{}, false
+	}
+	var best *Candidate
+	row, col := s.negativeRow(), s.negativeColumn()
+	for _, c := range s.Candidates() {
+		if row != -1 && c.Row != row || row == -1 && c.Col != col {
+			continue
+		}
+		if best == nil || c.Ratio.LessThan(*best.Ratio) {
+			best = &c
+		}
+	}
+	if best == nil {
+		return Candidate{}, false
+	}
+	return *best, true
+}
+
+// Check проверяет разрешающий элемент и возвращает предупреждения о шагах,
+// которые нарушают правила метода: потеря допустимости плана, рост
+// отрицательных свободных членов, не минимальное отношение CO
+func (s *Session) Check(row, col int) ([]string, error) {
+	t := s.Method.Table
+	if row < 0 || row >= t.Rows || col < 0 || col >= t.Cols-1 {
+		return nil, fmt.Errorf("pivot (%d, %d) is out of the table", row+1, col+1)
+	}
+	if t.Matrix[row][col].Equal(*fractional.ZeroValue) {
+		return nil, ErrZeroPivot
+	}
+	if _, ok := t.IsContainedInBasis(col); ok {
+		return nil, fmt.Errorf("%s is already a basis variable", t.VarName(col))
+	}
+
+	var warnings []string
+	var candidate *Candidate
+	candidates := s.Candidates()
+	for k := range candidates {
+		if candidates[k].Row == row && candidates[k].Col == col {
+			candidate = &candidates[k]
+		}
+	}
+	if candidate == nil {
+		warnings = append(warnings, fmt.Sprintf("%s is not a candidate pivot of the %s step", t.Matrix[row][col], s.Phase()))
+	} else {
+		for _, c := range candidates {
+			sameLine := c.Col == col
+			if s.negativeRow() != -1 {
+				sameLine = c.Row == row
+			}
+			if sameLine && c.Ratio.LessThan(*candidate.Ratio) {
+				warnings = append(warnings, fmt.Sprintf("CO = %s is not the minimum ratio %s", candidate.Ratio, c.Ratio))
+				break
+			}
+		}
+	}
+
+	next := &Method{Table: t.clone()}
+	if err := next.pivot(row, col); err != nil {
+		return nil, err
+	}
+	feasible := s.negativeRow() == -1
+	for i := range t.Rows {
+		if next.Table.Matrix[i][t.Cols-1].LessThan(*fractional.ZeroValue) && feasible {
+			warnings = append(warnings, fmt.Sprintf("pivot breaks feasibility: %s = %s", t.VarName(next.Table.BasisVars[i]), next.Table.Matrix[i][t.Cols-1]))
+		}
+	}
+	for j, z := range next.Table.Z {
+		if !feasible && z.LessThan(*fractional.ZeroValue) && !t.Z[j].LessThan(*fractional.ZeroValue) {
+			warnings = append(warnings, fmt.Sprintf("pivot breaks dual feasibility: Z(%s) = %s", t.VarName(j), z))
+		}
+	}
+	return warnings, nil
+}
+
+// Pivot делает шаг с разрешающим элементом (row, col) и запоминает его в истории
+func (s *Session) Pivot(row, col int) ([]string, error) {
+	warnings, err := s.Check(row, col)
+	if err != nil {
+		return nil, err
+	}
+	before := s.Method.Table.clone()
+	if err := s.Method.pivot(row, col); err != nil {
+		return nil, err
+	}
+	s.tables = append(s.tables, before)
+	s.History = append(s.History, Pivot{row, col, s.Method.Table.clone(), warnings})
+	return warnings, nil
+}
+
+// Undo возвращает таблицу, которая была до последнего шага
+func (s *Session) Undo() bool {
+	if len(s.tables) == 0 {
+		return false
+	}
+	last := len(s.tables) - 1
+	s.Method.Table = s.tables[last]
+	s.tables, s.History = s.tables[:last], s.History[:last]
+	return true
+}
+
+// String печатает текущую таблицу со столбцом или строкой CO предлагаемого шага
+func (s *Session) String() string {
+	t := s.Method.Table
+	m := s.Method
+	m.isDualMethod = s.negativeRow() != -1
+	if m.isDualMethod {
+		m.CO = make([]*fractional.Fraction, t.Cols-1)
+	} else {
+		m.CO = make([]*fractional.Fraction, t.Rows)
+	}
+	if suggested, ok := s.Suggest(); ok {
+		for _, c := range s.Candidates() {
+			switch {
+			case m.isDualMethod && c.Row == suggested.Row:
+				m.CO[c.Col] = c.Ratio
+			case !m.isDualMethod && c.Col == suggested.Col:
+				m.CO[c.Row] = c.Ratio
+			}
+		}
+	}
+	return m.String()
+}
+
+// Dump печатает все шаги решения
+func (s *Session) Dump() string {
+	var str string
+	for k, p := range s.History {
+		before := s.tables[k]
+		str += fmt.Sprintf("Step %d: pivot %s in row %d (%s), column %s\n", k+1,
+			before.Matrix[p.Row][p.Col], p.Row+1, before.VarName(before.BasisVars[p.Row]), before.VarName(p.Col))
+		for _, w := range p.Warnings {
+			str += fmt.Sprintf("warning: %s\n", w)
+		}
+		m := &Method{Table: p.Table, isDualMethod: true}
+		str += m.String() + "\n\n"
+	}
+	return str
+}
@@ -0,0 +1,89 @@
+package simplex_test
+
+import (
+	"errors"
+	"io"
+	"kw-algos/simplex"
+	"strings"
+	"testing"
+)
+
+// session начинает пошаговое решение задачи в алгебраической записи
+func session(t *testing.T, source string) *simplex.Session {
+	t.Helper()
+	table, err := simplex.ScanAlgebraic(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.SetOutput(io.Discard)
+	table.ToCanonicalForm()
+	basis, err := table.ToBasis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return simplex.NewSession(basis)
+}
+
+// TestSessionPivotUndo проверяет предупреждения о неверном разрешающем
+// элементе, отмену шага и решение по предложенным шагам
+func TestSessionPivotUndo(t *testing.T) {
+	s := session(t, "max: 3x1 + 2x2;\nc1: x1 + x2 <= 4;\nc2: x1 + 3x2 <= 6;\nc3: x1 <= 3;\n")
+	before := s.String()
+	if _, err := s.Pivot(2, 1); !errors.Is(err, simplex.ErrZeroPivot) {
+		t.Errorf("pivot on zero: expected ErrZeroPivot, got %v", err)
+	}
+	if _, err := s.Pivot(0, 2); err == nil {
+		t.Error("pivot on a basis variable: expected an error")
+	}
+
+	warnings, err := s.Pivot(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Join(warnings, "\n")
+	for _, expected := range []string{"CO = 6 is not the minimum ratio", "pivot breaks feasibility"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected warning %q, got:\n%s", expected, text)
+		}
+	}
+	if len(s.History) != 1 {
+		t.Fatalf("expected one step in the history, got %d", len(s.History))
+	}
+	if !s.Undo() || len(s.History) != 0 || s.String() != before {
+		t.Fatalf("undo did not restore the table:\n%s\nexpected:\n%s", s, before)
+	}
+	if s.Undo() {
+		t.Error("undo with an empty history succeeded")
+	}
+
+	for step := 0; ; step++ {
+		c, ok := s.Suggest()
+		if !ok {
+			break
+		}
+		if step == 10 {
+			t.Fatalf("no optimum after %d suggested steps:\n%s", step, s)
+		}
+		warnings, err := s.Pivot(c.Row, c.Col)
+		if err != nil || len(warnings) > 0 {
+			t.Fatalf("suggested pivot (%d, %d): %v %v", c.Row+1, c.Col+1, err, warnings)
+		}
+	}
+	if s.Phase() != simplex.Optimal {
+		t.Fatalf("expected the optimal phase, got %s:\n%s", s.Phase(), s)
+	}
+	if x := s.Method.Values(); x[0].String() != "3" || x[1].String() != "1" {
+		t.Errorf("expected x = (3; 1), got %v", x)
+	}
+}
+
+// TestSessionUnsolvable проверяет, что для таблицы без решений шаг не предлагается
+func TestSessionUnsolvable(t *testing.T) {
+	s := session(t, "max: -x1 - x2;\nc1: x1 + x2 >= 2;\nc2: -x1 - x2 >= 1;\n")
+	if s.Phase() != simplex.Unsolvable {
+		t.Fatalf("expected no solutions, got %s:\n%s", s.Phase(), s)
+	}
+	if c, ok := s.Suggest(); ok {
+		t.Errorf("suggested pivot (%d, %d) in a table without solutions:\n%s", c.Row+1, c.Col+1, s)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"kw-algos/fractional"
 	"math"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -29,7 +30,72 @@ type Table struct {
 	IsMinimizationProblem bool
 	BasisVars             []int
 	ZFree                 *fractional.Fraction
-	comparisons           []Comparison
+	VarNames              []string
+	RowNames              []string
+	// Integer отмечает целочисленные переменные (секции General/Binary),
+	// симплекс-метод решает непрерывную релаксацию
+	Integer []bool
+	// Substitutions выражают исходные переменные через переменные таблицы,
+	// если при чтении границ они были сдвинуты или разбиты на две; nil - без замен
+	Substitutions []Substitution
+	comparisons   []Comparison
+	isCanonical   bool
+	out           io.Writer
+	// original - задача до приведения к канонической форме, по ней проверяется ответ
+	original *Table
+}
+
+// Substitution - замена исходной переменной Name:
+// Name = x[Positive] - x[Negative] + Shift, Negative = -1, если разности нет
+type Substitution struct {
+	Name               string
+	Positive, Negative int
+	Shift              *fractional.Fraction
+}
+
+// Unsubstitute переводит значения переменных таблицы в значения исходных
+// переменных; без замен возвращает x как есть
+func (t *Table) Unsubstitute(x []*fractional.Fraction) []*fractional.Fraction {
+	if t.Substitutions == nil {
+		return x
+	}
+	result := make([]*fractional.Fraction, len(t.Substitutions))
+	for k, s := range t.Substitutions {
+		v := x[s.Positive].Add(*s.Shift)
+		if s.Negative >= 0 {
+			v = v.Subtract(*x[s.Negative])
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// NewTable собирает таблицу из строк ограничений, где последний элемент
+// каждой строки - правая часть, знаков сравнения и коэффициентов целевой функции
+func NewTable(matrix [][]*fractional.Fraction, comparisons []Comparison, z []*fractional.Fraction, isMinimization bool) *Table {
+	vars := len(z)
+	cols := vars + 1
+	rows := make([][]*fractional.Fraction, len(matrix))
+	for i, row := range matrix {
+		rows[i] = make([]*fractional.Fraction, cols, cols*2)
+		copy(rows[i], row)
+	}
+	Z := make([]*fractional.Fraction, vars)
+	copy(Z, z)
+	c := make([]Comparison, len(comparisons))
+	copy(c, comparisons)
+
+	return &Table{
+		Rows:                  len(matrix),
+		Cols:                  cols,
+		Vars:                  vars,
+		Matrix:                rows,
+		Z:                     Z,
+		IsMinimizationProblem: isMinimization,
+		BasisVars:             make([]int, len(matrix)),
+		ZFree:                 fractional.ZeroValue,
+		comparisons:           c,
+	}
 }
 
 func Scan(r io.Reader) (*Table, error) {
@@ -39,17 +105,34 @@ func Scan(r io.Reader) (*Table, error) {
 
 	line, _ := reader.ReadString('\n')
 	parts := strings.Fields(line)
-	rows, _ = strconv.Atoi(parts[0])
-	cols, _ = strconv.Atoi(parts[1])
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("expected the number of rows and columns, got %q", strings.TrimSpace(line))
+	}
+	rows, err := strconv.Atoi(parts[0])
+	if err != nil || rows < 1 {
+		return nil, fmt.Errorf("invalid number of rows: %s", parts[0])
+	}
+	cols, err = strconv.Atoi(parts[1])
+	if err != nil || cols < 1 {
+		return nil, fmt.Errorf("invalid number of columns: %s", parts[1])
+	}
 
 	vars = cols
 	cols++
 
-	matrix := make([][]*fractional.Fraction, rows)
+	// Строки добавляются по мере чтения, чтобы размер из заголовка не
+	// определял объём выделяемой памяти
+	matrix := make([][]*fractional.Fraction, 0)
 	for i := 0; i < rows; i++ {
-		matrix[i] = make([]*fractional.Fraction, cols, cols*2)
-		line, _ := reader.ReadString('\n')
+		line, err := reader.ReadString('\n')
 		parts := strings.Fields(line)
+		if len(parts) != vars+2 {
+			if err != nil {
+				return nil, fmt.Errorf("expected %d constraints, got %d", rows, i)
+			}
+			return nil, fmt.Errorf("constraint %d: expected %d coefficients, a sign and a free term", i+1, vars)
+		}
+		matrix = append(matrix, make([]*fractional.Fraction, cols, cols*2))
 		for j := 0; j < cols; j++ {
 			if j == vars {
 				// Считываем математический знак и результат для текущего уравнения
@@ -59,22 +142,15 @@ func Scan(r io.Reader) (*Table, error) {
 					return nil, err
 				}
 				comparisons = append(comparisons, comparison)
-				result, err := strconv.ParseInt(parts[j+1], 10, 64)
-				if err != nil {
-					return nil, err
-				}
-				matrix[i][cols-1], err = fractional.New(result, 1)
+				matrix[i][cols-1], err = fractional.Parse(parts[j+1])
 				if err != nil {
 					return nil, err
 				}
 				break
 			}
 			// Запись свободных переменных
-			value, err := strconv.ParseInt(parts[j], 10, 64)
-			if err != nil {
-				return nil, err
-			}
-			matrix[i][j], err = fractional.New(value, 1)
+			var err error
+			matrix[i][j], err = fractional.Parse(parts[j])
 			if err != nil {
 				return nil, err
 			}
@@ -84,47 +160,130 @@ func Scan(r io.Reader) (*Table, error) {
 	// Чтение строки для максимизации Z
 	line, _ = reader.ReadString('\n')
 	parts = strings.Fields(line)
+	if len(parts) != vars+2 {
+		return nil, fmt.Errorf("objective: expected %d coefficients, a free term and max or min", vars)
+	}
 	Z := make([]*fractional.Fraction, vars)
 	for j := 0; j < vars; j++ {
-		value, err := strconv.ParseInt(parts[j], 10, 64)
+		var err error
+		Z[j], err = fractional.Parse(parts[j])
 		if err != nil {
 			return nil, err
 		}
-		Z[j], err = fractional.New(value, 1)
-		if err != nil {
-			return nil, err
-		}
-	}
-	z, err := strconv.ParseInt(parts[vars], 10, 64)
-	if err != nil {
-		return nil, err
 	}
-	ZFree, err := fractional.New(z, 1)
+	ZFree, err := fractional.Parse(parts[vars])
 	if err != nil {
 		return nil, err
 	}
 
 	maxMinSign := parts[vars+1]
 	var isMinimization bool
-	if maxMinSign == "min" {
+	switch maxMinSign {
+	case "min":
 		isMinimization = true
-	} else {
+	case "max":
 		isMinimization = false
+	default:
+		return nil, fmt.Errorf("objective: expected max or min, got %s", maxMinSign)
 	}
 
 	return &Table{
-		rows,
-		cols,
-		vars,
-		matrix,
-		Z,
-		isMinimization,
-		make([]int, rows),
-		ZFree,
-		comparisons,
+		Rows:                  rows,
+		Cols:                  cols,
+		Vars:                  vars,
+		Matrix:                matrix,
+		Z:                     Z,
+		IsMinimizationProblem: isMinimization,
+		BasisVars:             make([]int, rows),
+		ZFree:                 ZFree,
+		comparisons:           comparisons,
 	}, nil
 }
 
+// WriteTo записывает задачу в формате, который читает Scan. Для таблицы в
+// канонической форме добавленные переменные записываются как обычные, а
+// знаки целевой функции задачи на минимум возвращаются к исходным
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	var s strings.Builder
+	comparisons := t.constraintComparisons()
+
+	fmt.Fprintf(&s, "%d %d\n", t.Rows, t.Cols-1)
+	for i := range t.Rows {
+		for j := range t.Cols - 1 {
+			fmt.Fprintf(&s, "%s ", t.Matrix[i][j])
+		}
+		fmt.Fprintf(&s, "%s %s\n", &comparisons[i], t.Matrix[i][t.Cols-1])
+	}
+	for _, z := range t.Objective() {
+		fmt.Fprintf(&s, "%s ", z)
+	}
+	sign := "max"
+	if t.IsMinimizationProblem {
+		sign = "min"
+	}
+	fmt.Fprintf(&s, "%s %s\n", t.ZFree, sign)
+
+	n, err := io.WriteString(w, s.String())
+	return int64(n), err
+}
+
+// SetOutput задаёт, куда печатаются промежуточные таблицы ToBasis и
+// симплекс-метода; по умолчанию это стандартный вывод
+func (t *Table) SetOutput(w io.Writer) {
+	t.out = w
+}
+
+func (t *Table) output() io.Writer {
+	if t.out == nil {
+		return os.Stdout
+	}
+	return t.out
+}
+
+// Comparisons возвращает знаки сравнения ограничений
+func (t *Table) Comparisons() []Comparison {
+	c := make([]Comparison, len(t.comparisons))
+	copy(c, t.comparisons)
+	return c
+}
+
+// Objective возвращает коэффициенты целевой функции в исходной постановке,
+// отменяя смену знака при приведении задачи на минимум к канонической форме
+func (t *Table) Objective() []*fractional.Fraction {
+	z := make([]*fractional.Fraction, t.Cols-1)
+	for j := range z {
+		z[j] = fractional.ZeroValue
+		if j < len(t.Z) {
+			z[j] = t.Z[j]
+		}
+		if t.isCanonical && t.IsMinimizationProblem {
+			z[j] = z[j].Reverse()
+		}
+	}
+	return z
+}
+
+// ObjectiveConstant возвращает свободный член целевой функции в исходной
+// постановке: для задачи на минимум симплекс-метод хранит его в ZFree с обратным знаком
+func (t *Table) ObjectiveConstant() *fractional.Fraction {
+	if t.IsMinimizationProblem {
+		return t.ZFree.Reverse()
+	}
+	return t.ZFree
+}
+
+func (t *Table) setObjectiveConstant(c *fractional.Fraction) {
+	if t.IsMinimizationProblem {
+		c = c.Reverse()
+	}
+	t.ZFree = c
+}
+
+// isInteger сообщает, отмечена ли переменная как целочисленная
+func (t *Table) isInteger(index int) bool {
+	return index < len(t.Integer) && t.Integer[index]
+}
+
 func parseComparison(sign string) (Comparison, error) {
 	switch sign {
 	case "<=":
@@ -138,9 +297,33 @@ func parseComparison(sign string) (Comparison, error) {
 	}
 }
 
+// VarName возвращает имя переменной из входных данных, либо xN для безымянных
+// и добавленных при приведении к канонической форме переменных
+func (t *Table) VarName(index int) string {
+	if index < len(t.VarNames) && t.VarNames[index] != "" {
+		return t.VarNames[index]
+	}
+	return fmt.Sprintf("x%d", index+1)
+}
+
+// RowName возвращает имя ограничения, либо пустую строку если оно не задано
+func (t *Table) RowName(index int) string {
+	if index < len(t.RowNames) {
+		return t.RowNames[index]
+	}
+	return ""
+}
+
 func (t *Table) String() string {
 	var s string
+	width := 0
+	for i := range t.Rows {
+		width = max(width, len(t.RowName(i)))
+	}
 	for i := 0; i < t.Rows; i++ {
+		if width > 0 {
+			s += fmt.Sprintf("%-*s:", width, t.RowName(i))
+		}
 		for j := 0; j < t.Cols; j++ {
 			s += fmt.Sprintf("%*s ", 8, t.Matrix[i][j])
 		}
@@ -150,6 +333,7 @@ func (t *Table) String() string {
 }
 
 func (t *Table) ToCanonicalForm() *Table {
+	t.original = t.clone()
 	var newColsCnt, beforeNormalization int
 	basis := make(map[int][]*fractional.Fraction)
 	var lasts []*fractional.Fraction
@@ -193,6 +377,7 @@ func (t *Table) ToCanonicalForm() *Table {
 			t.Z[i] = t.Z[i].Reverse()
 		}
 	}
+	t.isCanonical = true
 	return t
 }
 
@@ -225,7 +410,7 @@ func normalizeMatrix(matrix [][]*fractional.Fraction) [][]*fractional.Fraction {
 func (t *Table) previewBasis() error {
 	for i := range t.Rows {
 		for j := range t.Cols - 1 {
-			if t.Matrix[i][j].Equal(*fractional.OneValue) || t.Matrix[i][j].Equal(*fractional.RevOneValue) {
+			if (t.Matrix[i][j].Equal(*fractional.OneValue) || t.Matrix[i][j].Equal(*fractional.RevOneValue)) && t.isUnitColumn(i, j) {
 				t.BasisVars[i] = j
 				if t.Matrix[i][j].Equal(*fractional.RevOneValue) {
 					for col := range t.Cols {
@@ -238,6 +423,16 @@ func (t *Table) previewBasis() error {
 	return nil
 }
 
+// isUnitColumn проверяет, что в столбце j все элементы, кроме строки i, нулевые
+func (t *Table) isUnitColumn(i, j int) bool {
+	for k := range t.Rows {
+		if k != i && t.Matrix[k][j].NotEqual(*fractional.ZeroValue) {
+			return false
+		}
+	}
+	return true
+}
+
 func (t *Table) ToBasis() (*Table, error) {
 	for i := range t.BasisVars {
 		t.BasisVars[i] = -1
@@ -254,7 +449,14 @@ func (t *Table) ToBasis() (*Table, error) {
 		if t.BasisVars[i] != -1 {
 			continue
 		}
-		fmt.Println(t)
+		// Столбцы закончились: оставшиеся строки либо линейно зависимы, либо противоречивы
+		if columOfResolver >= t.Cols-1 {
+			if _, err := t.checkRank(); err != nil {
+				return nil, err
+			}
+			return t, nil
+		}
+		fmt.Fprintln(t.output(), t)
 
 		t.swapMatrixRows(i, columOfResolver)
 		needToCheck := false
@@ -267,7 +469,7 @@ func (t *Table) ToBasis() (*Table, error) {
 				t.swapMatrixRows(i, j)
 
 				if !t.Matrix[i][j].Equal(*fractional.ZeroValue) {
-					newMatrix = t.Matrix
+					newMatrix = t.CopyMatrix()
 					needToCheck = false
 					columOfResolver = j + 1
 					currentColumOfResolver = j
@@ -295,7 +497,7 @@ func (t *Table) ToBasis() (*Table, error) {
 			columOfResolver++
 		}
 
-		if t.Matrix[i][currentColumOfResolver].NotEqual(*fractional.RevOneValue) {
+		if t.Matrix[i][currentColumOfResolver].NotEqual(*fractional.OneValue) {
 			divider := t.Matrix[i][currentColumOfResolver]
 			for j := 0; j < t.Cols; j++ {
 				var err error
@@ -327,6 +529,10 @@ func (t *Table) swapMatrixRows(startRow, startColumn int) {
 	maxValue := math.Abs(t.Matrix[startRow][startColumn].Float64())
 	maxIndex := startRow
 	for j := startRow; j < t.Rows; j++ {
+		// Строки, уже получившие базисную переменную, не переставляются
+		if t.BasisVars[j] != -1 {
+			continue
+		}
 		if maxValue < math.Abs(t.Matrix[j][startColumn].Float64()) {
 			maxValue = math.Abs(t.Matrix[j][startColumn].Float64())
 			maxIndex = j
@@ -337,6 +543,9 @@ func (t *Table) swapMatrixRows(startRow, startColumn int) {
 		copy(copyRow, t.Matrix[startRow])
 		t.Matrix[startRow] = t.Matrix[maxIndex]
 		t.Matrix[maxIndex] = copyRow
+		if len(t.RowNames) == t.Rows {
+			t.RowNames[startRow], t.RowNames[maxIndex] = t.RowNames[maxIndex], t.RowNames[startRow]
+		}
 	}
 }
 
@@ -360,7 +569,7 @@ func (t *Table) checkRank() (int, error) {
 	}
 
 	if rank != extendedRank {
-		return -1, fmt.Errorf("no solution")
+		return -1, ErrNoSolutions
 	} else if rank < t.Cols-1 {
 		return 1, nil
 	}
@@ -372,7 +581,7 @@ func (t *Table) methodRectangle(newMatrix [][]*fractional.Fraction, resolveRow,
 		if i == resolveRow {
 			continue
 		}
-		for j := resolveColumn; j < t.Cols; j++ {
+		for j := 0; j < t.Cols; j++ {
 			if j == resolveColumn && i != resolveRow {
 				newMatrix[i][j] = fractional.ZeroValue
 			} else {
@@ -407,6 +616,12 @@ func (t *Table) IsContainedInBasis(index int) (int, bool) {
 	return -1, false
 }
 
+// Clone возвращает копию таблицы, которую не меняют упрощение, масштабирование
+// и приведение к канонической форме исходной таблицы
+func (t *Table) Clone() *Table {
+	return t.clone()
+}
+
 func (t *Table) CopyMatrix() [][]*fractional.Fraction {
 	newMatrix := make([][]*fractional.Fraction, t.Rows)
 	for r := range newMatrix {
@@ -0,0 +1,43 @@
+package simplex
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func FuzzScan(f *testing.F) {
+	for _, s := range []string{
+		"2 2\n1 1 <= 4\n1 3 >= 6\n2 3 0 max\n",
+		"3 5\n13 10 -1 0 0 = 130\n-1 2 0 1 0 = 10\n2 7 0 0 1 = 14\n-5 -6 0 0 0 0 max\n",
+		"1 2\n1/2 -0.25 >= -1\n1 1 3/2 min\n",
+		"2 1\n1 <= 1\n",
+		"0 0\n",
+		"1\n",
+		"",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		table, err := Scan(strings.NewReader(s))
+		if err != nil {
+			return
+		}
+		var buf bytes.Buffer
+		if _, err := table.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		written := buf.String()
+		again, err := Scan(&buf)
+		if err != nil {
+			t.Fatalf("written table does not scan: %s\n%s", err, written)
+		}
+		var rewritten strings.Builder
+		if _, err := again.WriteTo(&rewritten); err != nil {
+			t.Fatal(err)
+		}
+		if rewritten.String() != written {
+			t.Fatalf("table changed after WriteTo and Scan:\n%s\n%s", written, rewritten.String())
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package simplex_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"kw-algos/simplex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden trace files in testdata/trace")
+
+// trace решает задачу так же, как команда solve, и возвращает всё, что
+// печатается по ходу решения: каноническую форму, таблицы метода
+// Жордана-Гаусса и симплекс-метода, ответ и его доказательство
+func trace(source []byte) (string, error) {
+	t, err := simplex.Scan(bytes.NewReader(source))
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	t.SetOutput(&out)
+	fmt.Fprintf(&out, "%s\n", t.ToCanonicalForm())
+	fmt.Fprintln(&out, "Jordan Gauss:")
+	table, err := t.ToBasis()
+	if err != nil {
+		fmt.Fprintln(&out, err)
+		return out.String(), nil
+	}
+	fmt.Fprintf(&out, "%s\n", t)
+
+	fmt.Fprintln(&out, "Dual Simplex method:")
+	method := simplex.New(table)
+	method.MaxIterations = 1000
+	solveErr := method.DualMethod()
+	if solveErr != nil && !errors.Is(solveErr, simplex.ErrNoSolutions) {
+		return "", solveErr
+	}
+	if solveErr != nil {
+		fmt.Fprintln(&out, solveErr)
+	}
+	certificate, err := method.Verify()
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&out, "verified %s\n", certificate)
+	return out.String(), nil
+}
+
+// TestTrace сравнивает напечатанный ход решения задач из testdata/trace с
+// файлами .golden. После намеренного изменения вывода файлы
+// перезаписываются командой go test ./simplex -run TestTrace -update
+func TestTrace(t *testing.T) {
+	problems, err := filepath.Glob(filepath.Join("testdata", "trace", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) == 0 {
+		t.Fatal("no problems in testdata/trace")
+	}
+	for _, path := range problems {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := trace(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := strings.TrimSuffix(path, ".txt") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, []byte(actual), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%s (run go test -update to create it)", err)
+			}
+			if actual != string(expected) {
+				t.Errorf("trace differs from %s (- expected, + actual):\n%s", golden, diff(string(expected), actual))
+			}
+		})
+	}
+}
+
+// diff сравнивает тексты построчно по наибольшей общей подпоследовательности
+// и печатает отличающиеся строки с тремя строками контекста
+func diff(expected, actual string) string {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+	// lcs[i][j] - длина общей подпоследовательности a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i, j = i+1, j+1
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	// Печатаются изменённые строки и по три строки контекста вокруг них
+	const context = 3
+	show := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op != ' ' {
+			for c := max(k-context, 0); c <= min(k+context, len(lines)-1); c++ {
+				show[c] = true
+			}
+		}
+	}
+	var s strings.Builder
+	for k, l := range lines {
+		if !show[k] {
+			continue
+		}
+		if k > 0 && !show[k-1] {
+			s.WriteString("...\n")
+		}
+		fmt.Fprintf(&s, "%c %s\n", l.op, l.text)
+	}
+	return s.String()
+}
@@ -0,0 +1,249 @@
+package simplex
+
+import (
+	"errors"
+	"fmt"
+	"kw-algos/fractional"
+)
+
+// maxBasisCandidates ограничивает полный перебор наборов базисных столбцов
+const maxBasisCandidates = 1_000_000
+
+var ErrNotCanonical = errors.New("table is not in canonical form")
+
+// Vertex - вершина допустимого многогранника: значения всех переменных
+// канонической формы, значение целевой функции и базисы, которые её задают
+// (несколько базисов у вырожденной вершины)
+type Vertex struct {
+	X     []*fractional.Fraction
+	Value *fractional.Fraction
+	Bases [][]int
+}
+
+// Polyhedron - вершины допустимого множества и их смежность: Adjacent[k] -
+// номера вершин, соседних с вершиной k по ребру многогранника
+type Polyhedron struct {
+	Table    *Table
+	Vertices []*Vertex
+	Adjacent [][]int
+}
+
+// Vertices перебирает все наборы из rank столбцов матрицы ограничений
+// канонической формы, решает систему методом Жордана-Гаусса и оставляет
+// допустимые базисные решения. Вершины смежны, если их базисы отличаются одним столбцом
+func (t *Table) Vertices() (*Polyhedron, error) {
+	if !t.isCanonical {
+		return nil, ErrNotCanonical
+	}
+	n := t.Cols - 1
+	rows, ok := independentRows(t.Matrix, n)
+	p := &Polyhedron{Table: t}
+	if !ok {
+		return p, nil
+	}
+	m := len(rows)
+	if candidates := binomial(n, m); candidates < 0 || candidates > maxBasisCandidates {
+		return nil, fmt.Errorf("too many bases to enumerate: C(%d, %d)", n, m)
+	}
+
+	objective := t.Objective()
+	constant := t.ObjectiveConstant()
+	var bases [][]int
+	var owners []int
+	combinations(n, m, func(basis []int) {
+		x, ok := basicSolution(rows, basis, n)
+		if !ok {
+			return
+		}
+		index := -1
+		for k, v := range p.Vertices {
+			if equalVectors(v.X, x) {
+				index = k
+				break
+			}
+		}
+		if index == -1 {
+			index = len(p.Vertices)
+			p.Vertices = append(p.Vertices, &Vertex{
+				X:     x,
+				Value: dot(objective, x).Add(*constant),
+			})
+		}
+		b := append([]int(nil), basis...)
+		p.Vertices[index].Bases = append(p.Vertices[index].Bases, b)
+		bases = append(bases, b)
+		owners = append(owners, index)
+	})
+
+	p.Adjacent = make([][]int, len(p.Vertices))
+	for a := range bases {
+		for b := a + 1; b < len(bases); b++ {
+			u, v := owners[a], owners[b]
+			if u == v || contains(p.Adjacent[u], v) || common(bases[a], bases[b]) != m-1 {
+				continue
+			}
+			p.Adjacent[u] = append(p.Adjacent[u], v)
+			p.Adjacent[v] = append(p.Adjacent[v], u)
+		}
+	}
+	return p, nil
+}
+
+// independentRows приводит систему [A|b] к ступенчатому виду и возвращает её
+// ненулевые строки. false означает, что система несовместна
+func independentRows(matrix [][]*fractional.Fraction, n int) ([][]*fractional.Fraction, bool) {
+	rows := make([][]*fractional.Fraction, len(matrix))
+	for i := range matrix {
+		rows[i] = append([]*fractional.Fraction(nil), matrix[i][:n+1]...)
+	}
+	rank := 0
+	for j := 0; j < n && rank < len(rows); j++ {
+		pivot := -1
+		for i := rank; i < len(rows); i++ {
+			if rows[i][j].NotEqual(*fractional.ZeroValue) {
+				pivot = i
+				break
+			}
+		}
+		if pivot == -1 {
+			continue
+		}
+		rows[rank], rows[pivot] = rows[pivot], rows[rank]
+		eliminate(rows, rank, j)
+		rank++
+	}
+	for i := rank; i < len(rows); i++ {
+		if rows[i][n].NotEqual(*fractional.ZeroValue) {
+			return nil, false
+		}
+	}
+	return rows[:rank], true
+}
+
+// basicSolution решает систему относительно столбцов basis, остальные
+// переменные равны нулю. false - базис вырожден или решение недопустимо
+func basicSolution(matrix [][]*fractional.Fraction, basis []int, n int) ([]*fractional.Fraction, bool) {
+	rows := make([][]*fractional.Fraction, len(matrix))
+	for i := range matrix {
+		rows[i] = append([]*fractional.Fraction(nil), matrix[i]...)
+	}
+	for k, j := range basis {
+		pivot := -1
+		for i := k; i < len(rows); i++ {
+			if rows[i][j].NotEqual(*fractional.ZeroValue) {
+				pivot = i
+				break
+			}
+		}
+		if pivot == -1 {
+			return nil, false
+		}
+		rows[k], rows[pivot] = rows[pivot], rows[k]
+		eliminate(rows, k, j)
+	}
+
+	x := make([]*fractional.Fraction, n)
+	for j := range x {
+		x[j] = fractional.ZeroValue
+	}
+	for k, j := range basis {
+		if rows[k][n].LessThan(*fractional.ZeroValue) {
+			return nil, false
+		}
+		x[j] = rows[k][n]
+	}
+	return x, true
+}
+
+// eliminate делит строку r на элемент столбца j и исключает столбец j из остальных строк
+func eliminate(rows [][]*fractional.Fraction, r, j int) {
+	pivot := rows[r][j]
+	for c := range rows[r] {
+		rows[r][c], _ = rows[r][c].Divide(*pivot)
+	}
+	for i := range rows {
+		if i == r || rows[i][j].Equal(*fractional.ZeroValue) {
+			continue
+		}
+		factor := rows[i][j]
+		for c := range rows[i] {
+			rows[i][c] = rows[i][c].Subtract(*factor.Multiply(*rows[r][c]))
+		}
+	}
+}
+
+// combinations вызывает visit для каждого возрастающего набора из k чисел от 0 до n-1
+func combinations(n, k int, visit func([]int)) {
+	if k > n {
+		return
+	}
+	c := make([]int, k)
+	for i := range c {
+		c[i] = i
+	}
+	for {
+		visit(c)
+		i := k - 1
+		for i >= 0 && c[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		c[i]++
+		for j := i + 1; j < k; j++ {
+			c[j] = c[j-1] + 1
+		}
+	}
+}
+
+// binomial возвращает C(n, k) или -1 при переполнении
+func binomial(n, k int) int {
+	result := 1
+	for i := 1; i <= k; i++ {
+		result = result * (n - k + i) / i
+		if result < 0 || result > maxBasisCandidates*1000 {
+			return -1
+		}
+	}
+	return result
+}
+
+func common(a, b []int) int {
+	count := 0
+	for _, x := range a {
+		if contains(b, x) {
+			count++
+		}
+	}
+	return count
+}
+
+func equalVectors(a, b []*fractional.Fraction) bool {
+	for i := range a {
+		if a[i].NotEqual(*b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Polyhedron) String() string {
+	if len(p.Vertices) == 0 {
+		return "no feasible points"
+	}
+	var s string
+	for k, v := range p.Vertices {
+		s += fmt.Sprintf("v%d = %s, Z = %s, adjacent:", k+1, formatVector(v.X), v.Value)
+		for _, a := range p.Adjacent[k] {
+			s += fmt.Sprintf(" v%d", a+1)
+		}
+		if len(v.Bases) > 1 {
+			s += fmt.Sprintf(" (degenerate, %d bases)", len(v.Bases))
+		}
+		if k != len(p.Vertices)-1 {
+			s += "\n"
+		}
+	}
+	return s
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"kw-algos/simplex"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const stepHelp = `commands:
+  <Enter>        accept the suggested pivot
+  <row> <col>    pivot on the element, by numbers (1-based) or names: 2 3, x4 x1
+  u              undo the last pivot
+  h              print the history
+  q              quit`
+
+// runStep решает задачу по шагам: пользователь выбирает разрешающий элемент
+// в каждой таблице, команды читаются из стандартного ввода
+func runStep(args []string) int {
+	fs := newFlagSet("step")
+	format := fs.String("f", "matrix", "(input format) matrix | algebraic | lp | mps")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() != 1 || fs.Arg(0) == "-" {
+		return failf(exitUsage, "step reads commands from standard input, pass the problem as a file")
+	}
+	r, err := input(fs.Arg(0))
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+	t, err := readTable(r, *format)
+	_ = r.Close()
+	if err != nil {
+		return failf(exitUsage, "%s", err)
+	}
+
+	t.SetOutput(io.Discard)
+	t.ToCanonicalForm()
+	table, err := t.ToBasis()
+	if err != nil {
+		fmt.Println(err)
+		return exitFailure
+	}
+	session := simplex.NewSession(table)
+	fmt.Println(stepHelp)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Printf("\n%s\n\n", session)
+		phase := session.Phase()
+		suggested, ok := session.Suggest()
+		switch phase {
+		case simplex.Optimal:
+			x := session.Method.Values()
+			fmt.Printf("optimal: Z = %s, x = %v\n", session.Method.Table.ObjectiveConstant(), x)
+		case simplex.Unsolvable:
+			fmt.Println("no solutions")
+		default:
+			fmt.Printf("%s step, candidates:", phase)
+			for _, c := range session.Candidates() {
+				fmt.Printf(" (%d, %s) CO = %s;", c.Row+1, table.VarName(c.Col), c.Ratio)
+			}
+			fmt.Println()
+			if ok {
+				fmt.Printf("suggested: row %d (%s), column %s\n", suggested.Row+1,
+					table.VarName(session.Method.Table.BasisVars[suggested.Row]), table.VarName(suggested.Col))
+			}
+		}
+		fmt.Print("> ")
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "q" || line == "" && !ok {
+			break
+		}
+		switch line {
+		case "u":
+			if !session.Undo() {
+				fmt.Println("nothing to undo")
+			}
+			continue
+		case "h":
+			fmt.Print(session.Dump())
+			continue
+		case "?", "help":
+			fmt.Println(stepHelp)
+			continue
+		}
+
+		row, col := suggested.Row, suggested.Col
+		if line != "" {
+			if row, col, err = parsePivot(session.Method.Table, line); err != nil {
+				fmt.Println(err)
+				continue
+			}
+		}
+		warnings, err := session.Pivot(row, col)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		for _, w := range warnings {
+			fmt.Printf("warning: %s\n", w)
+		}
+	}
+
+	fmt.Printf("\nHistory:\n%s", session.Dump())
+	if session.Phase() != simplex.Optimal {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// parsePivot разбирает строку и столбец разрешающего элемента: номера с
+// единицы, имя базисной переменной строки и имя переменной столбца
+func parsePivot(t *simplex.Table, line string) (int, int, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected a row and a column, got %q", line)
+	}
+	row, col := -1, -1
+	if n, err := strconv.Atoi(fields[0]); err == nil {
+		row = n - 1
+	} else {
+		for i, v := range t.BasisVars {
+			if t.VarName(v) == fields[0] {
+				row = i
+			}
+		}
+	}
+	if n, err := strconv.Atoi(fields[1]); err == nil {
+		col = n - 1
+	} else {
+		for j := range t.Cols - 1 {
+			if t.VarName(j) == fields[1] {
+				col = j
+			}
+		}
+	}
+	if row < 0 || row >= t.Rows {
+		return 0, 0, fmt.Errorf("unknown row: %s", fields[0])
+	}
+	if col < 0 || col >= t.Cols-1 {
+		return 0, 0, fmt.Errorf("unknown column: %s", fields[1])
+	}
+	return row, col, nil
+}
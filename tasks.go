@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"kw-algos/assignment"
+	"kw-algos/fractional"
+	"kw-algos/games"
+	"kw-algos/graphical"
+	"kw-algos/network"
+	"kw-algos/simplex"
+	"kw-algos/transport"
+	"os"
+)
+
+func solveTransport(r io.Reader, plan string) error {
+	methods := map[string]transport.Method{
+		"nw":    transport.NorthWestCorner,
+		"min":   transport.MinimumCost,
+		"vogel": transport.Vogel,
+	}
+	method, ok := methods[plan]
+	if !ok {
+		return fmt.Errorf("unknown initial plan method: %s", plan)
+	}
+	p, err := transport.Scan(r)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Initial plan (%s):\n", method)
+	p.InitialPlan(method)
+	fmt.Printf("%s\n\n", p)
+	fmt.Println("Method of potentials:")
+	return p.Potentials()
+}
+
+func solveAssignment(r io.Reader) error {
+	p, err := assignment.Scan(r)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Hungarian method:")
+	p.Solve(true)
+	fmt.Println(p.Report())
+
+	// Проверка: та же задача как задача линейного программирования
+	x, err := solveQuietly(p.ToTable())
+	if err != nil {
+		return err
+	}
+	total := fractional.ZeroValue
+	for k, x := range x {
+		total = total.Add(*x.Multiply(*p.Matrix[k/p.N][k%p.N]))
+	}
+	if total.NotEqual(*p.Total()) {
+		return fmt.Errorf("simplex method disagrees: F = %s", total)
+	}
+	fmt.Println("Simplex method agrees")
+	return nil
+}
+
+func solveGame(r io.Reader) error {
+	g, err := games.Scan(r)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n\n", g)
+	if err := g.Solve(os.Stdout); err != nil {
+		return err
+	}
+	fmt.Println(g)
+	return nil
+}
+
+func solveFlow(r io.Reader) error {
+	n, err := network.Scan(r)
+	if err != nil {
+		return err
+	}
+	var value, cost *fractional.Fraction
+	if n.Amount == nil {
+		fmt.Println("Edmonds-Karp:")
+		value = n.MaxFlow(os.Stdout)
+		fmt.Println(n)
+		fmt.Print("minimum cut:")
+		for _, v := range n.MinCut() {
+			fmt.Printf(" %d", v+1)
+		}
+		fmt.Println()
+	} else {
+		fmt.Println("Successive shortest paths:")
+		if value, cost, err = n.MinCostFlow(os.Stdout); err != nil {
+			return err
+		}
+		fmt.Println(n)
+	}
+
+	// Проверка: та же задача как задача линейного программирования
+	x, err := solveQuietly(n.ToTable())
+	if err != nil {
+		return err
+	}
+	if n.Amount == nil {
+		lpValue := fractional.ZeroValue
+		for k, arc := range n.Arcs {
+			if arc.From == n.Source {
+				lpValue = lpValue.Add(*x[k])
+			}
+			if arc.To == n.Source {
+				lpValue = lpValue.Subtract(*x[k])
+			}
+		}
+		if lpValue.NotEqual(*value) {
+			return fmt.Errorf("simplex method disagrees: flow = %s", lpValue)
+		}
+	} else {
+		lpCost := fractional.ZeroValue
+		for k, arc := range n.Arcs {
+			lpCost = lpCost.Add(*x[k].Multiply(*arc.Cost))
+		}
+		if lpCost.NotEqual(*cost) {
+			return fmt.Errorf("simplex method disagrees: cost = %s", lpCost)
+		}
+	}
+	fmt.Println("Simplex method agrees")
+	return nil
+}
+
+// solveQuietly решает задачу двойственным симплекс-методом без печати таблиц
+// и возвращает значения исходных переменных
+func solveQuietly(t *simplex.Table) ([]*fractional.Fraction, error) {
+	t.SetOutput(io.Discard)
+	t.ToCanonicalForm()
+	basis, err := t.ToBasis()
+	if err != nil {
+		return nil, err
+	}
+	method := simplex.New(basis)
+	if err := method.DualMethod(); err != nil {
+		return nil, err
+	}
+	return method.Values()[:t.Vars], nil
+}
+
+func solveGoals(r io.Reader, mode string) error {
+	gp, err := simplex.ScanGoals(r)
+	if err != nil {
+		return err
+	}
+	var result *simplex.GoalResult
+	switch mode {
+	case "lexicographic":
+		result, err = gp.Lexicographic()
+	case "weighted":
+		result, err = gp.Weighted()
+	default:
+		return fmt.Errorf("unknown goal programming mode: %s", mode)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func solveRatio(r io.Reader) error {
+	fp, err := simplex.ScanFractional(r)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Charnes-Cooper:")
+	x, ratio, err := fp.Solve()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nx = %v\nratio = %s\n", x, ratio)
+	return nil
+}
+
+func solveGraphical(t *simplex.Table, svg string) error {
+	p, err := graphical.New(t)
+	if err != nil {
+		return err
+	}
+	s := p.Solve()
+	fmt.Println(s)
+	if svg == "" {
+		return nil
+	}
+	f, err := os.Create(svg)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteSVG(f, s); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
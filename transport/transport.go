@@ -0,0 +1,572 @@
+package transport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"strconv"
+	"strings"
+)
+
+type Method int
+
+const (
+	NorthWestCorner Method = iota
+	MinimumCost
+	Vogel
+)
+
+func (m Method) String() string {
+	return [...]string{"north-west corner", "minimum cost", "Vogel"}[m]
+}
+
+// Problem - транспортная задача: Cost[i][j] - стоимость перевозки единицы
+// груза от поставщика i потребителю j, Plan - текущий план перевозок,
+// Basis отмечает базисные клетки плана, U и V - потенциалы поставщиков и потребителей
+type Problem struct {
+	Rows, Cols int
+	Cost       [][]*fractional.Fraction
+	Supply     []*fractional.Fraction
+	Demand     []*fractional.Fraction
+	Plan       [][]*fractional.Fraction
+	Basis      [][]bool
+	U, V       []*fractional.Fraction
+	// Фиктивные поставщик или потребитель, добавленные при балансировке
+	DummyRow, DummyCol bool
+	// Alternative - оптимальный план не единственный, заполняется методом потенциалов
+	Alternative bool
+}
+
+// Scan считывает задачу: первая строка - число поставщиков m и потребителей n,
+// затем m строк со стоимостями перевозок и запасом поставщика в конце,
+// последняя строка - потребности n потребителей
+func Scan(r io.Reader) (*Problem, error) {
+	scanner := bufio.NewScanner(r)
+	var lines [][]string
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		if fields := strings.Fields(line); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || len(lines[0]) != 2 {
+		return nil, fmt.Errorf("expected header with number of suppliers and consumers")
+	}
+	rows, err := strconv.Atoi(lines[0][0])
+	if err != nil {
+		return nil, err
+	}
+	cols, err := strconv.Atoi(lines[0][1])
+	if err != nil {
+		return nil, err
+	}
+	if rows < 1 || cols < 1 {
+		return nil, fmt.Errorf("expected positive numbers of suppliers and consumers, got %d and %d", rows, cols)
+	}
+	if len(lines) != rows+2 {
+		return nil, fmt.Errorf("expected %d lines after header, got %d", rows+1, len(lines)-1)
+	}
+
+	parse := func(fields []string, n int) ([]*fractional.Fraction, error) {
+		if len(fields) != n {
+			return nil, fmt.Errorf("expected %d values, got %d: %q", n, len(fields), strings.Join(fields, " "))
+		}
+		values := make([]*fractional.Fraction, n)
+		for j, field := range fields {
+			var err error
+			if values[j], err = fractional.Parse(field); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+
+	cost := make([][]*fractional.Fraction, rows)
+	supply := make([]*fractional.Fraction, rows)
+	for i := range rows {
+		values, err := parse(lines[i+1], cols+1)
+		if err != nil {
+			return nil, err
+		}
+		cost[i], supply[i] = values[:cols], values[cols]
+	}
+	demand, err := parse(lines[rows+1], cols)
+	if err != nil {
+		return nil, err
+	}
+	return New(cost, supply, demand)
+}
+
+// New создаёт задачу и балансирует её фиктивным поставщиком или потребителем
+func New(cost [][]*fractional.Fraction, supply, demand []*fractional.Fraction) (*Problem, error) {
+	if len(cost) != len(supply) {
+		return nil, fmt.Errorf("cost matrix has %d rows, but there are %d suppliers", len(cost), len(supply))
+	}
+	p := &Problem{
+		Rows:   len(supply),
+		Cols:   len(demand),
+		Supply: append([]*fractional.Fraction(nil), supply...),
+		Demand: append([]*fractional.Fraction(nil), demand...),
+		Cost:   make([][]*fractional.Fraction, len(supply)),
+	}
+	for i, row := range cost {
+		if len(row) != p.Cols {
+			return nil, fmt.Errorf("cost row %d has %d values, but there are %d consumers", i+1, len(row), p.Cols)
+		}
+		p.Cost[i] = append([]*fractional.Fraction(nil), row...)
+	}
+	for _, v := range append(p.Supply, p.Demand...) {
+		if v.LessThan(*fractional.ZeroValue) {
+			return nil, fmt.Errorf("supply and demand must be non-negative")
+		}
+	}
+	p.balance()
+	return p, nil
+}
+
+// balance добавляет фиктивного потребителя или поставщика с нулевыми
+// стоимостями, если суммарный запас не равен суммарной потребности
+func (p *Problem) balance() {
+	diff := sum(p.Supply).Subtract(*sum(p.Demand))
+	switch {
+	case diff.GreaterThan(*fractional.ZeroValue):
+		for i := range p.Cost {
+			p.Cost[i] = append(p.Cost[i], fractional.ZeroValue)
+		}
+		p.Demand = append(p.Demand, diff)
+		p.Cols++
+		p.DummyCol = true
+	case diff.LessThan(*fractional.ZeroValue):
+		row := make([]*fractional.Fraction, p.Cols)
+		for j := range row {
+			row[j] = fractional.ZeroValue
+		}
+		p.Cost = append(p.Cost, row)
+		p.Supply = append(p.Supply, diff.Abs())
+		p.Rows++
+		p.DummyRow = true
+	}
+}
+
+// InitialPlan строит опорный план выбранным методом
+func (p *Problem) InitialPlan(method Method) {
+	p.Plan = make([][]*fractional.Fraction, p.Rows)
+	p.Basis = make([][]bool, p.Rows)
+	for i := range p.Rows {
+		p.Plan[i] = make([]*fractional.Fraction, p.Cols)
+		p.Basis[i] = make([]bool, p.Cols)
+		for j := range p.Cols {
+			p.Plan[i][j] = fractional.ZeroValue
+		}
+	}
+	supply := append([]*fractional.Fraction(nil), p.Supply...)
+	demand := append([]*fractional.Fraction(nil), p.Demand...)
+	rowDone := make([]bool, p.Rows)
+	colDone := make([]bool, p.Cols)
+
+	allocate := func(i, j int) {
+		x := supply[i]
+		if demand[j].LessThan(*x) {
+			x = demand[j]
+		}
+		p.Plan[i][j] = x
+		p.Basis[i][j] = true
+		supply[i] = supply[i].Subtract(*x)
+		demand[j] = demand[j].Subtract(*x)
+		// При одновременном исчерпании вычёркивается только строка,
+		// чтобы план оставался связным; недостающие клетки добавит fillDegenerate
+		if supply[i].Equal(*fractional.ZeroValue) {
+			rowDone[i] = true
+		} else {
+			colDone[j] = true
+		}
+	}
+
+	switch method {
+	case NorthWestCorner:
+		for i, j := 0, 0; i < p.Rows && j < p.Cols; {
+			allocate(i, j)
+			if rowDone[i] {
+				i++
+			} else {
+				j++
+			}
+		}
+	case MinimumCost:
+		for {
+			i, j := p.cheapestCell(rowDone, colDone, -1, -1)
+			if i == -1 {
+				break
+			}
+			allocate(i, j)
+		}
+	case Vogel:
+		for {
+			i, j := p.vogelCell(rowDone, colDone)
+			if i == -1 {
+				break
+			}
+			allocate(i, j)
+		}
+	}
+	p.fillDegenerate()
+}
+
+// cheapestCell находит клетку с наименьшей стоимостью среди невычеркнутых,
+// ограничиваясь строкой row или столбцом col, если они заданы
+func (p *Problem) cheapestCell(rowDone, colDone []bool, row, col int) (int, int) {
+	bestI, bestJ := -1, -1
+	for i := range p.Rows {
+		if rowDone[i] || (row != -1 && i != row) {
+			continue
+		}
+		for j := range p.Cols {
+			if colDone[j] || (col != -1 && j != col) {
+				continue
+			}
+			if bestI == -1 || p.Cost[i][j].LessThan(*p.Cost[bestI][bestJ]) {
+				bestI, bestJ = i, j
+			}
+		}
+	}
+	return bestI, bestJ
+}
+
+// vogelCell выбирает строку или столбец с наибольшим штрафом (разностью двух
+// наименьших стоимостей) и в нём клетку с наименьшей стоимостью
+func (p *Problem) vogelCell(rowDone, colDone []bool) (int, int) {
+	penalty := func(costs []*fractional.Fraction) *fractional.Fraction {
+		var first, second *fractional.Fraction
+		for _, c := range costs {
+			switch {
+			case first == nil || c.LessThan(*first):
+				first, second = c, first
+			case second == nil || c.LessThan(*second):
+				second = c
+			}
+		}
+		if second == nil {
+			return first
+		}
+		return second.Subtract(*first)
+	}
+
+	var best *fractional.Fraction
+	row, col := -1, -1
+	for i := range p.Rows {
+		if rowDone[i] {
+			continue
+		}
+		var costs []*fractional.Fraction
+		for j := range p.Cols {
+			if !colDone[j] {
+				costs = append(costs, p.Cost[i][j])
+			}
+		}
+		if len(costs) == 0 {
+			continue
+		}
+		if d := penalty(costs); best == nil || d.GreaterThan(*best) {
+			best, row, col = d, i, -1
+		}
+	}
+	for j := range p.Cols {
+		if colDone[j] {
+			continue
+		}
+		var costs []*fractional.Fraction
+		for i := range p.Rows {
+			if !rowDone[i] {
+				costs = append(costs, p.Cost[i][j])
+			}
+		}
+		if len(costs) == 0 {
+			continue
+		}
+		if d := penalty(costs); best == nil || d.GreaterThan(*best) {
+			best, row, col = d, -1, j
+		}
+	}
+	if best == nil {
+		return -1, -1
+	}
+	return p.cheapestCell(rowDone, colDone, row, col)
+}
+
+// fillDegenerate дополняет вырожденный план нулевыми базисными клетками
+// наименьшей стоимости, не образующими цикла, до m+n-1 клеток
+func (p *Problem) fillDegenerate() {
+	parent := make([]int, p.Rows+p.Cols)
+	for k := range parent {
+		parent[k] = k
+	}
+	var find func(int) int
+	find = func(k int) int {
+		if parent[k] != k {
+			parent[k] = find(parent[k])
+		}
+		return parent[k]
+	}
+	count := 0
+	for i := range p.Rows {
+		for j := range p.Cols {
+			if p.Basis[i][j] {
+				parent[find(i)] = find(p.Rows + j)
+				count++
+			}
+		}
+	}
+	for count < p.Rows+p.Cols-1 {
+		bestI, bestJ := -1, -1
+		for i := range p.Rows {
+			for j := range p.Cols {
+				if p.Basis[i][j] || find(i) == find(p.Rows+j) {
+					continue
+				}
+				if bestI == -1 || p.Cost[i][j].LessThan(*p.Cost[bestI][bestJ]) {
+					bestI, bestJ = i, j
+				}
+			}
+		}
+		p.Basis[bestI][bestJ] = true
+		parent[find(bestI)] = find(p.Rows + bestJ)
+		count++
+	}
+}
+
+// Potentials улучшает план методом потенциалов, печатая каждую итерацию,
+// пока все оценки свободных клеток не станут неотрицательными
+func (p *Problem) Potentials() error {
+	for {
+		if err := p.computePotentials(); err != nil {
+			return err
+		}
+		fmt.Println(p)
+
+		enterI, enterJ := -1, -1
+		var minDelta *fractional.Fraction
+		p.Alternative = false
+		for i := range p.Rows {
+			for j := range p.Cols {
+				if p.Basis[i][j] {
+					continue
+				}
+				delta := p.Delta(i, j)
+				// Нулевая оценка даёт другой оптимальный план, только если
+				// по её циклу можно перевезти ненулевое количество груза
+				if delta.Equal(*fractional.ZeroValue) && !p.Alternative {
+					if cycle := p.cycle(i, j); cycle != nil {
+						theta, _ := p.theta(cycle)
+						p.Alternative = theta.GreaterThan(*fractional.ZeroValue)
+					}
+				}
+				if minDelta == nil || delta.LessThan(*minDelta) {
+					minDelta, enterI, enterJ = delta, i, j
+				}
+			}
+		}
+		if minDelta == nil || !minDelta.LessThan(*fractional.ZeroValue) {
+			if p.Alternative {
+				fmt.Println("plan is optimal, but not the only one")
+			}
+			return nil
+		}
+
+		cycle := p.cycle(enterI, enterJ)
+		if cycle == nil {
+			return fmt.Errorf("no cycle for cell (%d, %d)", enterI+1, enterJ+1)
+		}
+		theta, leaving := p.theta(cycle)
+		fmt.Printf("enter (%d, %d), leave (%d, %d), θ = %s\n\n",
+			enterI+1, enterJ+1, cycle[leaving][0]+1, cycle[leaving][1]+1, theta)
+		for k, cell := range cycle {
+			i, j := cell[0], cell[1]
+			if k%2 == 0 {
+				p.Plan[i][j] = p.Plan[i][j].Add(*theta)
+			} else {
+				p.Plan[i][j] = p.Plan[i][j].Subtract(*theta)
+			}
+		}
+		p.Basis[enterI][enterJ] = true
+		p.Basis[cycle[leaving][0]][cycle[leaving][1]] = false
+		p.Plan[cycle[leaving][0]][cycle[leaving][1]] = fractional.ZeroValue
+	}
+}
+
+// theta возвращает наименьшую перевозку в клетках цикла со знаком - и её
+// номер в цикле: в цикле чередуются клетки со знаками + и -, начиная с вводимой
+func (p *Problem) theta(cycle [][2]int) (*fractional.Fraction, int) {
+	theta := p.Plan[cycle[1][0]][cycle[1][1]]
+	leaving := 1
+	for k := 3; k < len(cycle); k += 2 {
+		if x := p.Plan[cycle[k][0]][cycle[k][1]]; x.LessThan(*theta) {
+			theta, leaving = x, k
+		}
+	}
+	return theta, leaving
+}
+
+// computePotentials находит u и v из условий u[i] + v[j] = Cost[i][j] для
+// базисных клеток, полагая u[0] = 0
+func (p *Problem) computePotentials() error {
+	p.U = make([]*fractional.Fraction, p.Rows)
+	p.V = make([]*fractional.Fraction, p.Cols)
+	p.U[0] = fractional.ZeroValue
+	for changed := true; changed; {
+		changed = false
+		for i := range p.Rows {
+			for j := range p.Cols {
+				if !p.Basis[i][j] {
+					continue
+				}
+				switch {
+				case p.U[i] != nil && p.V[j] == nil:
+					p.V[j] = p.Cost[i][j].Subtract(*p.U[i])
+					changed = true
+				case p.U[i] == nil && p.V[j] != nil:
+					p.U[i] = p.Cost[i][j].Subtract(*p.V[j])
+					changed = true
+				}
+			}
+		}
+	}
+	for _, u := range append(p.U, p.V...) {
+		if u == nil {
+			return fmt.Errorf("basis is not connected, potentials are undefined")
+		}
+	}
+	return nil
+}
+
+// Delta - оценка свободной клетки: Cost[i][j] - (u[i] + v[j])
+func (p *Problem) Delta(i, j int) *fractional.Fraction {
+	return p.Cost[i][j].Subtract(*p.U[i].Add(*p.V[j]))
+}
+
+// cycle находит цикл пересчёта, начинающийся во вводимой клетке: путь по
+// базисным клеткам от строки i до столбца j, чередующий строки и столбцы
+func (p *Problem) cycle(i, j int) [][2]int {
+	// Вершины графа: строки 0..Rows-1 и столбцы Rows..Rows+Cols-1
+	prev := make([]int, p.Rows+p.Cols)
+	for k := range prev {
+		prev[k] = -1
+	}
+	prev[i] = i
+	queue := []int{i}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for other := range p.Rows + p.Cols {
+			if prev[other] != -1 || !p.isEdge(node, other) {
+				continue
+			}
+			prev[other] = node
+			queue = append(queue, other)
+		}
+	}
+	if prev[p.Rows+j] == -1 {
+		return nil
+	}
+	cycle := [][2]int{{i, j}}
+	for node := p.Rows + j; node != i; node = prev[node] {
+		cycle = append(cycle, p.cell(node, prev[node]))
+	}
+	return cycle
+}
+
+func (p *Problem) isEdge(a, b int) bool {
+	if (a < p.Rows) == (b < p.Rows) {
+		return false
+	}
+	cell := p.cell(a, b)
+	return p.Basis[cell[0]][cell[1]]
+}
+
+func (p *Problem) cell(a, b int) [2]int {
+	if a < p.Rows {
+		return [2]int{a, b - p.Rows}
+	}
+	return [2]int{b, a - p.Rows}
+}
+
+// TotalCost - суммарная стоимость перевозок по текущему плану
+func (p *Problem) TotalCost() *fractional.Fraction {
+	total := fractional.ZeroValue
+	for i := range p.Rows {
+		for j := range p.Cols {
+			total = total.Add(*p.Cost[i][j].Multiply(*p.Plan[i][j]))
+		}
+	}
+	return total
+}
+
+func (p *Problem) String() string {
+	var s string
+	offset := 12
+
+	s += fmt.Sprintf("%*s|", 5, "")
+	for j := range p.Cols {
+		s += fmt.Sprintf("%*s", offset, p.colName(j))
+	}
+	s += fmt.Sprintf(" |%*s", offset/2+2, "a")
+	if p.U != nil {
+		s += fmt.Sprintf(" |%*s", offset/2, "u")
+	}
+	s += "\n"
+	for i := range p.Rows {
+		s += fmt.Sprintf(" %-4s|", p.rowName(i))
+		for j := range p.Cols {
+			cell := p.Cost[i][j].String()
+			if p.Basis != nil && p.Basis[i][j] {
+				cell += fmt.Sprintf("[%s]", p.Plan[i][j])
+			}
+			s += fmt.Sprintf("%*s", offset, cell)
+		}
+		s += fmt.Sprintf(" |%*s", offset/2+2, p.Supply[i])
+		if p.U != nil {
+			s += fmt.Sprintf(" |%*s", offset/2, p.U[i])
+		}
+		s += "\n"
+	}
+	s += fmt.Sprintf(" %-4s|", "b")
+	for j := range p.Cols {
+		s += fmt.Sprintf("%*s", offset, p.Demand[j])
+	}
+	if p.V != nil {
+		s += fmt.Sprintf(" |\n %-4s|", "v")
+		for j := range p.Cols {
+			s += fmt.Sprintf("%*s", offset, p.V[j])
+		}
+	}
+	s += " |"
+	if p.Plan != nil {
+		s += fmt.Sprintf("\n F = %s", p.TotalCost())
+	}
+	return s
+}
+
+func (p *Problem) rowName(i int) string {
+	if p.DummyRow && i == p.Rows-1 {
+		return "A*"
+	}
+	return fmt.Sprintf("A%d", i+1)
+}
+
+func (p *Problem) colName(j int) string {
+	if p.DummyCol && j == p.Cols-1 {
+		return "B*"
+	}
+	return fmt.Sprintf("B%d", j+1)
+}
+
+func sum(values []*fractional.Fraction) *fractional.Fraction {
+	total := fractional.ZeroValue
+	for _, v := range values {
+		total = total.Add(*v)
+	}
+	return total
+}
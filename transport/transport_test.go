@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"kw-algos/fractional"
+	"strings"
+	"testing"
+)
+
+// solve читает задачу, строит опорный план методом method и улучшает его методом потенциалов
+func solve(t *testing.T, source string, method Method) *Problem {
+	t.Helper()
+	p, err := Scan(strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("%q: %s", source, err)
+	}
+	p.InitialPlan(method)
+	if err := p.Potentials(); err != nil {
+		t.Fatalf("%q, %s: %s", source, method, err)
+	}
+	return p
+}
+
+// TestPotentials проверяет стоимость оптимального плана при каждом способе
+// построения опорного плана и то, что план вывозит запасы и покрывает потребности
+func TestPotentials(t *testing.T) {
+	for _, c := range []struct {
+		source, cost string
+	}{
+		{"3 4\n2 3 2 4 30\n3 2 5 1 40\n4 3 2 6 20\n20 30 30 10\n", "170"},
+		{"2 2\n1 2 10\n3 4 10\n5 5\n", "15"},
+		{"2 3\n4 6 8 30\n5 3 7 20\n20 20 20\n", "220"},
+	} {
+		for _, method := range []Method{NorthWestCorner, MinimumCost, Vogel} {
+			p := solve(t, c.source, method)
+			if cost := p.TotalCost().String(); cost != c.cost {
+				t.Errorf("%q, %s: F = %s, expected %s", c.source, method, cost, c.cost)
+			}
+			for i := range p.Rows {
+				if s := sum(p.Plan[i]); s.NotEqual(*p.Supply[i]) {
+					t.Errorf("%q, %s: supplier %d ships %s of %s", c.source, method, i+1, s, p.Supply[i])
+				}
+			}
+			for j := range p.Cols {
+				column := make([]*fractional.Fraction, p.Rows)
+				for i := range p.Rows {
+					column[i] = p.Plan[i][j]
+				}
+				if s := sum(column); s.NotEqual(*p.Demand[j]) {
+					t.Errorf("%q, %s: consumer %d receives %s of %s", c.source, method, j+1, s, p.Demand[j])
+				}
+			}
+		}
+	}
+}
+
+// TestAlternative проверяет, что другой оптимальный план сообщается только
+// для нулевой оценки, цикл которой переносит ненулевой груз
+func TestAlternative(t *testing.T) {
+	for _, c := range []struct {
+		source      string
+		alternative bool
+	}{
+		{"2 2\n1 2 10\n3 4 10\n5 5\n", false},
+		{"3 4\n2 3 2 4 30\n3 2 5 1 40\n4 3 2 6 20\n20 30 30 10\n", false},
+		{"2 2\n1 1 5\n1 1 5\n5 5\n", true},
+	} {
+		for _, method := range []Method{NorthWestCorner, MinimumCost, Vogel} {
+			if p := solve(t, c.source, method); p.Alternative != c.alternative {
+				t.Errorf("%q, %s: alternative %t, expected %t", c.source, method, p.Alternative, c.alternative)
+			}
+		}
+	}
+}
+
+// TestScanErrors проверяет, что неверные заголовки и размеры дают ошибку, а не панику
+func TestScanErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"2\n",
+		"0 2\n5 5\n",
+		"-1 2\n1 2 10\n5 5\n",
+		"2 -1\n1 10\n3 10\n5\n",
+		"a 2\n1 2 10\n5 5\n",
+		"2 2\n1 2 10\n5 5\n",
+		"2 2\n1 2 10\n3 4\n5 5\n",
+		"1 1\n1 -10\n10\n",
+	} {
+		if _, err := Scan(strings.NewReader(source)); err == nil {
+			t.Errorf("%q: expected an error", source)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package verify
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"kw-algos/fractional"
+	"kw-algos/simplex"
+	"strings"
+)
+
+type Status int
+
+const (
+	Optimal Status = iota
+	// Alternative - оптимальное решение не единственное (аннотация //inf)
+	Alternative
+	// NoSolutions - задача несовместна или целевая функция неограничена (аннотация //no)
+	NoSolutions
+)
+
+func (s Status) String() string {
+	return [...]string{"optimal", "inf", "no"}[s]
+}
+
+// Expectation - ожидаемый ответ из аннотации задачи: //no, //inf или значение
+// целевой функции, например //20
+type Expectation struct {
+	Status Status
+	Value  *fractional.Fraction
+}
+
+func (e *Expectation) String() string {
+	if e.Status == Optimal {
+		return e.Value.String()
+	}
+	return e.Status.String()
+}
+
+// Case - задача из файла вместе с номером её первой строки и ожидаемым
+// ответом. Expected равен nil, если у задачи нет аннотации
+type Case struct {
+	Line     int
+	Source   string
+	Expected *Expectation
+}
+
+// Result - ответ, полученный симплекс-методом, и итог сравнения с аннотацией
+type Result struct {
+	Case   *Case
+	Actual *Expectation
+	Err    error
+}
+
+// Passed сообщает, совпал ли ответ с ожидаемым. Задачи без аннотации не проверяются
+func (r *Result) Passed() bool {
+	if r.Err != nil {
+		return false
+	}
+	expected := r.Case.Expected
+	if expected == nil {
+		return true
+	}
+	if expected.Status != r.Actual.Status {
+		return false
+	}
+	return expected.Status != Optimal || expected.Value.Equal(*r.Actual.Value)
+}
+
+// Scan разбивает файл в формате test.txt на задачи, разделённые пустыми
+// строками. Комментарий из одного слова no, inf или числа после задачи
+// считается ожидаемым ответом, остальные комментарии пропускаются
+func Scan(r io.Reader) ([]*Case, error) {
+	scanner := bufio.NewScanner(r)
+	var cases []*Case
+	var current *Case
+	var lines []string
+	flush := func() {
+		if current != nil {
+			current.Source = strings.Join(lines, "\n") + "\n"
+			cases = append(cases, current)
+		}
+		current, lines = nil, nil
+	}
+
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "":
+			flush()
+		case strings.HasPrefix(text, "//"):
+			if current != nil && current.Expected == nil {
+				current.Expected = parseAnnotation(text)
+			}
+		default:
+			if current == nil {
+				current = &Case{Line: n}
+			}
+			lines = append(lines, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return cases, nil
+}
+
+func parseAnnotation(comment string) *Expectation {
+	fields := strings.Fields(strings.TrimPrefix(comment, "//"))
+	if len(fields) != 1 {
+		return nil
+	}
+	switch fields[0] {
+	case "no":
+		return &Expectation{Status: NoSolutions}
+	case "inf":
+		return &Expectation{Status: Alternative}
+	}
+	value, err := fractional.Parse(fields[0])
+	if err != nil {
+		return nil
+	}
+	return &Expectation{Status: Optimal, Value: value}
+}
+
+// Run решает задачу двойственным симплекс-методом без печати таблиц
+func (c *Case) Run() *Result {
+	result := &Result{Case: c}
+	t, err := simplex.Scan(strings.NewReader(c.Source))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Actual, result.Err = solve(t)
+	return result
+}
+
+func solve(t *simplex.Table) (*Expectation, error) {
+	t.SetOutput(io.Discard)
+	t.ToCanonicalForm()
+	objective := t.Objective()
+	constant := t.ObjectiveConstant()
+
+	basis, err := t.ToBasis()
+	if errors.Is(err, simplex.ErrNoSolutions) {
+		return &Expectation{Status: NoSolutions}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	method := simplex.New(basis)
+	err = method.DualMethod()
+	if err != nil && !errors.Is(err, simplex.ErrNoSolutions) {
+		return nil, err
+	}
+	if _, err := method.Verify(); err != nil {
+		return nil, fmt.Errorf("certificate check failed: %w", err)
+	}
+	if method.Face == nil {
+		return &Expectation{Status: NoSolutions}, nil
+	}
+
+	if method.Face.Truncated {
+		return nil, simplex.ErrFaceTruncated
+	}
+	if len(method.Face.Vertices) > 1 || len(method.Face.Rays) > 0 {
+		return &Expectation{Status: Alternative}, nil
+	}
+	value := constant
+	for j, x := range method.Values() {
+		value = value.Add(*objective[j].Multiply(*x))
+	}
+	return &Expectation{Status: Optimal, Value: value}, nil
+}
+
+// Run проверяет все задачи и печатает по строке на задачу и итоговую сводку.
+// Возвращает число несовпадений
+func Run(cases []*Case, w io.Writer) int {
+	var passed, failed, skipped int
+	for k, c := range cases {
+		r := c.Run()
+		fmt.Fprintf(w, "problem %d (line %d): ", k+1, c.Line)
+		switch {
+		case r.Err != nil:
+			fmt.Fprintf(w, "FAIL: %s\n", strings.TrimSpace(r.Err.Error()))
+		case c.Expected == nil:
+			fmt.Fprintf(w, "%s, no annotation\n", r.Actual)
+		case r.Passed():
+			fmt.Fprintf(w, "ok (%s)\n", r.Actual)
+		default:
+			fmt.Fprintf(w, "FAIL: expected %s, got %s\n", c.Expected, r.Actual)
+		}
+		switch {
+		case !r.Passed():
+			failed++
+		case c.Expected == nil:
+			skipped++
+		default:
+			passed++
+		}
+	}
+	fmt.Fprintf(w, "\npassed %d, failed %d, skipped %d\n", passed, failed, skipped)
+	return failed
+}
@@ -0,0 +1,75 @@
+package verify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestScan проверяет разбиение файла на задачи, номера их первых строк и
+// разбор аннотаций; комментарии из нескольких слов аннотацией не считаются
+func TestScan(t *testing.T) {
+	source := "// header comment\n\n1 1\n1 <= 4\n1 0 max\n//4\n\n\n1 1\n1 >= 1\n1 0 max\n// 1 0 max yes\n//no\n\n" +
+		"2 2\n1 1 <= 1\n1 1 >= 0\n1 1 0 max\n//inf\n\n1 1\n1 <= 1\n1 0 max\n"
+	cases, err := Scan(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		line       int
+		annotation string
+	}{
+		{3, "4"},
+		{9, "no"},
+		{15, "inf"},
+		{21, ""},
+	}
+	if len(cases) != len(expected) {
+		t.Fatalf("got %d cases, expected %d", len(cases), len(expected))
+	}
+	for k, c := range cases {
+		annotation := ""
+		if c.Expected != nil {
+			annotation = c.Expected.String()
+		}
+		if c.Line != expected[k].line || annotation != expected[k].annotation {
+			t.Errorf("case %d: line %d, annotation %q, expected line %d and %q", k+1, c.Line, annotation, expected[k].line, expected[k].annotation)
+		}
+	}
+}
+
+// TestRun проверяет исходы задач и подсчёт совпадений с аннотациями
+func TestRun(t *testing.T) {
+	for _, c := range []struct {
+		source, actual string
+		passed         bool
+	}{
+		{"3 3\n2 1 5 >= 12\n1 0 8 >= 16\n5 2 1 >= 10\n6 1 4 0 min\n//12\n", "12", true},
+		{"3 2\n4 7 >= 57\n4 1 >= 15\n3 11 >= 60\n1 7 0 min\n//21\n", "20", false},
+		{"2 2\n1 1 <= 1\n1 1 >= 2\n1 1 0 max\n//no\n", "no", true},
+		{"1 2\n1 -1 <= 1\n1 1 0 max\n//no\n", "no", true},
+		{"1 2\n1 1 <= 4\n1 1 0 max\n//inf\n", "inf", true},
+		{"1 2\n1 1 <= 4\n1 1 0 max\n//4\n", "inf", false},
+	} {
+		cases, err := Scan(strings.NewReader(c.source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := cases[0].Run()
+		if r.Err != nil {
+			t.Fatalf("%q: %s", c.source, r.Err)
+		}
+		if r.Actual.String() != c.actual || r.Passed() != c.passed {
+			t.Errorf("%q: got %s, passed %t, expected %s and %t", c.source, r.Actual, r.Passed(), c.actual, c.passed)
+		}
+	}
+
+	cases, err := Scan(strings.NewReader("1 1\n1 <= 4\n1 0 max\n//4\n\n1 1\n1 <= 4\n1 0 max\n//5\n\n1 1\n1 <= 4\n1 0 max\n\n1 1\nx <= 4\n1 0 max\n//4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w bytes.Buffer
+	if failed := Run(cases, &w); failed != 2 || !strings.HasSuffix(w.String(), "passed 1, failed 2, skipped 1\n") {
+		t.Errorf("failed %d:\n%s", failed, w.String())
+	}
+}